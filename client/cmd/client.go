@@ -23,7 +23,7 @@ func main() {
 	authUI1 := authUI.New(auth)
 	gameUI1 := gameUI.New(game)
 
-	ui := terminalUI.New(authUI1, gameUI1)
+	ui := terminalUI.New(authUI1, gameUI1, rmq)
 
 	ui.MustRun()
 }
@@ -1,8 +1,9 @@
 package rabbitmq
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"github.com/streadway/amqp"
 )
 
@@ -12,37 +13,125 @@ const (
 	Ready MessageType = iota
 	Attack
 	Result
-	End // send by losing user
+	End       // send by the server after the last ship is destroyed or a player left
+	Heartbeat // send to the server during the battle to show the player is still here
+	Chat      // a chat message between the opponents, passed by the server
 )
 
+// end reasons
+const (
+	AllSunk     = "all_ships_sunk"
+	Forfeit     = "forfeit"
+	Abandoned   = "abandoned"
+	TurnTimeout = "turn_timeout"
+	Cancelled   = "cancelled"
+)
+
+const ( // queue names
+	gameBattle = "game.battle"
+	gameResume = "game.resume"
+)
+
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
 // Message structure:
-// Ready { empty };
+// Ready { Ships } to the server, Ready { First bool } from the server;
 // Attack {X, Y int }
-// Result { Hit, Destroy bool }
+// Result { Attacker string, X, Y int, Hit, Destroy bool }
+// End { Attacker string, X, Y int, Hit, Destroy bool, Winner, Reason string }
+// Chat { Text string } to the server, Chat { From, Text string } from the server
+// Err is set by the server if the request was rejected
 type Message struct {
-	Type    MessageType `json:"type"`
-	X       int         `json:"x,omitempty"`
-	Y       int         `json:"y,omitempty"`
-	Hit     bool        `json:"hit,omitempty"`
-	Destroy bool        `json:"destroy,omitempty"`
+	Type     MessageType `json:"type"`
+	X        int         `json:"x,omitempty"`
+	Y        int         `json:"y,omitempty"`
+	Ships    [][]Point   `json:"ships,omitempty"`
+	First    bool        `json:"first,omitempty"`
+	Attacker string      `json:"attacker,omitempty"`
+	Hit      bool        `json:"hit,omitempty"`
+	Destroy  bool        `json:"destroy,omitempty"`
+	Winner   string      `json:"winner,omitempty"`
+	Reason   string      `json:"reason,omitempty"`
+	From     string      `json:"from,omitempty"`
+	Text     string      `json:"text,omitempty"`
+	Err      string      `json:"error,omitempty"`
+}
+
+type resumeRequest struct {
+	Forfeit bool `json:"forfeit,omitempty"`
+}
+
+// Shot is a shot resolved by the server, Destroy is set if the ship of the cell is sunk
+type Shot struct {
+	X       int  `json:"x"`
+	Y       int  `json:"y"`
+	Hit     bool `json:"hit,omitempty"`
+	Destroy bool `json:"destroy,omitempty"`
+}
+
+// GameState is the state of the running game kept by the server, Opponent is empty if there is no such game
+type GameState struct {
+	Opponent      string    `json:"opponent,omitempty"`
+	FleetPlaced   bool      `json:"fleet_placed,omitempty"`
+	Started       bool      `json:"started,omitempty"`
+	MyTurn        bool      `json:"my_turn,omitempty"`
+	Fleet         [][]Point `json:"fleet,omitempty"`
+	MyShots       []Shot    `json:"my_shots,omitempty"`
+	OpponentShots []Shot    `json:"opponent_shots,omitempty"`
+	Err           string    `json:"error,omitempty"`
+}
+
+// GetGameState asks the server for the running game of the player
+func (r *RabbitMQ) GetGameState() (GameState, error) {
+	state, err := r.resume(resumeRequest{})
+	if err != nil {
+		return GameState{}, err
+	}
+	if state.Opponent != "" {
+		r.player2Login = state.Opponent
+	}
+	return state, nil
+}
+
+// ForfeitGame gives the running game of the player up
+func (r *RabbitMQ) ForfeitGame() error {
+	_, err := r.resume(resumeRequest{Forfeit: true})
+	return err
 }
 
+func (r *RabbitMQ) resume(req resumeRequest) (GameState, error) {
+	var response GameState
+	err := r.call(context.Background(), gameResume, req, &response)
+	if err != nil {
+		return GameState{}, err
+	}
+	if response.Err != "" {
+		return GameState{}, errors.New(response.Err)
+	}
+	return response, nil
+}
+
+// GetterMessages returns the battle messages of the personal queue. They are decoded by a single goroutine
+// for the whole life of the client, the battles read the same channel one after another.
 func (r *RabbitMQ) GetterMessages() (<-chan Message, error) {
-	msgs := make(chan Message)
-	go func() {
-		for d := range r.msgs {
-			var msg Message
-			err := json.Unmarshal(d.Body, &msg)
-			if err != nil {
-				continue
+	r.battleOnce.Do(func() {
+		r.battle = make(chan Message)
+		go func() {
+			for d := range r.msgs {
+				var msg Message
+				err := json.Unmarshal(d.Body, &msg)
+				if err != nil {
+					continue
+				}
+				r.battle <- msg
 			}
-			msgs <- msg
-			fmt.Println("Get message: ", msg)
-		}
-		close(msgs)
-	}()
-	fmt.Println("Get messages ", r.player1Login)
-	return msgs, nil
+			close(r.battle)
+		}()
+	})
+	return r.battle, nil
 }
 
 func (r *RabbitMQ) SendMessage(msg Message) error {
@@ -51,20 +140,40 @@ func (r *RabbitMQ) SendMessage(msg Message) error {
 		return err
 	}
 
-	err = r.ch.Publish(
+	err = r.channel().Publish(
 		"",
-		r.player2Login,
+		gameBattle,
 		false,
 		false,
 		amqp.Publishing{
 			ContentType: "application/json",
 			Body:        body,
 			ReplyTo:     r.player1Login,
+			Headers:     r.headers(),
 		},
 	)
-	fmt.Println("Sending message to ", r.player2Login, " ", msg)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// SendHeartbeat tells the server the player is still in the game
+func (r *RabbitMQ) SendHeartbeat() error {
+	body, err := json.Marshal(Message{Type: Heartbeat})
+	if err != nil {
+		return err
+	}
+
+	return r.channel().Publish(
+		"",
+		gameBattle,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+			Headers:     r.headers(),
+		},
+	)
+}
@@ -1,41 +1,160 @@
 package rabbitmq
 
 import (
-	"github.com/streadway/amqp"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/streadway/amqp"
 )
 
 type RabbitMQ struct {
+	url  string
+	mu   sync.RWMutex // guards conn and ch, they are replaced on reconnect
 	conn *amqp.Connection
 	ch   *amqp.Channel
-	msgs <-chan amqp.Delivery
+	msgs chan amqp.Delivery // battle messages of the personal queue, kept across reconnects
 	que  amqp.Queue
 
+	battleOnce sync.Once
+	battle     chan Message // the decoded msgs, see GetterMessages
+
+	// the responses to the requests come to the reply queue and are passed to the calls by the correlation id
+	replyQueue string
+	pendingMu  sync.Mutex
+	pending    map[string]chan amqp.Delivery // correlation id -> the call waiting for the response
+	lastID     atomic.Uint64
+
+	states chan bool // false when the connection is lost, true when it is restored
+	closed chan struct{}
+
 	timeout time.Duration
 
 	player1Login string
 	player2Login string
+	token        string // session token issued at login
 }
 
+// Delays between the attempts to connect to the server, doubled after every failed attempt
+const (
+	minReconnectDelay = 500 * time.Millisecond
+	maxReconnectDelay = 10 * time.Second
+)
+
 func New(url string, timeout time.Duration) *RabbitMQ {
-	conn, err := amqp.Dial(url)
+	r := &RabbitMQ{
+		url:        url,
+		msgs:       make(chan amqp.Delivery),
+		replyQueue: newReplyQueueName(),
+		pending:    make(map[string]chan amqp.Delivery),
+		states:     make(chan bool, 8),
+		closed:     make(chan struct{}),
+		timeout:    timeout,
+	}
+
+	err := r.dial()
+	if err == nil {
+		err = r.initReplies()
+	}
 	if err != nil {
 		panic(err)
 	}
+	go r.supervise()
+
+	return r
+}
+
+// ConnectionStates reports the connection to the server going down (false) and being restored (true)
+func (r *RabbitMQ) ConnectionStates() <-chan bool {
+	return r.states
+}
+
+func (r *RabbitMQ) dial() error {
+	conn, err := amqp.Dial(r.url)
+	if err != nil {
+		return err
+	}
 	ch, err := conn.Channel()
 	if err != nil {
-		panic(err)
+		conn.Close()
+		return err
 	}
 
-	return &RabbitMQ{
-		conn:    conn,
-		ch:      ch,
-		timeout: timeout,
+	r.mu.Lock()
+	r.conn, r.ch = conn, ch
+	r.mu.Unlock()
+	return nil
+}
+
+// supervise reconnects to the server every time the connection is lost and restores the reply and the personal queues
+func (r *RabbitMQ) supervise() {
+	for {
+		r.mu.RLock()
+		lost := r.conn.NotifyClose(make(chan *amqp.Error, 1))
+		r.mu.RUnlock()
+
+		select {
+		case <-r.closed:
+			return
+		case <-lost:
+		}
+		select {
+		case <-r.closed:
+			return
+		default:
+		}
+		r.notifyState(false)
+
+		delay := minReconnectDelay
+		for {
+			select {
+			case <-r.closed:
+				return
+			case <-time.After(delay):
+			}
+			err := r.dial()
+			if err == nil {
+				// the exclusive queues were deleted with the old connection
+				err = r.initReplies()
+			}
+			if err == nil && r.player1Login != "" {
+				err = r.initQueue()
+			}
+			if err == nil {
+				break
+			}
+			delay = min(2*delay, maxReconnectDelay)
+		}
+		r.notifyState(true)
 	}
 }
 
-func (r *RabbitMQ) initQueue() {
-	q, err := r.ch.QueueDeclare(
+func (r *RabbitMQ) notifyState(connected bool) {
+	select {
+	case r.states <- connected:
+	default: // nobody is watching
+	}
+}
+
+// channel returns the channel of the current connection
+func (r *RabbitMQ) channel() *amqp.Channel {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.ch
+}
+
+// tokenHeader is the message header with the session token checked by the server
+const tokenHeader = "token"
+
+// headers returns the headers attached to every message sent after login
+func (r *RabbitMQ) headers() amqp.Table {
+	return amqp.Table{tokenHeader: r.token}
+}
+
+// initQueue declares the personal queue of the player and forwards its messages to r.msgs.
+// The queue carries only the battle messages, the responses to the requests come to the reply queue.
+func (r *RabbitMQ) initQueue() error {
+	q, err := r.channel().QueueDeclare(
 		r.player1Login, // name
 		false,          // durable
 		false,          // delete when unused
@@ -44,10 +163,10 @@ func (r *RabbitMQ) initQueue() {
 		nil,            // arguments
 	)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	msgs, err := r.ch.Consume(
+	msgs, err := r.channel().Consume(
 		q.Name, // queue
 		"",     // consumer
 		true,   // auto-ack
@@ -57,17 +176,26 @@ func (r *RabbitMQ) initQueue() {
 		nil,    // args
 	)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	r.que = q
-	r.msgs = msgs
+	go func() {
+		// ends when the consumer is cancelled or the connection is lost
+		for d := range msgs {
+			r.msgs <- d
+		}
+	}()
+	return nil
 }
 
 func (r *RabbitMQ) Close() {
-	_ = r.ch.Cancel(r.que.Name, false)
-	_, _ = r.ch.QueueDelete(r.que.Name, false, false, false)
+	close(r.closed)
+	_ = r.channel().Cancel(r.que.Name, false)
+	_, _ = r.channel().QueueDelete(r.que.Name, false, false, false)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	_ = r.ch.Close()
 	_ = r.conn.Close()
-
 }
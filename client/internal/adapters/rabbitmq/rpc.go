@@ -0,0 +1,123 @@
+package rabbitmq
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/streadway/amqp"
+)
+
+var ErrTimeout = errors.New("timeout")
+
+// newReplyQueueName returns a name of the reply queue unique to this client.
+// The name is kept across reconnects, so the responses sent later by the server, e.g. when the opponent joins, still arrive.
+func newReplyQueueName() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "reply." + hex.EncodeToString(b)
+}
+
+// initReplies declares the reply queue and starts the consumer passing the responses to the waiting calls
+func (r *RabbitMQ) initReplies() error {
+	q, err := r.channel().QueueDeclare(
+		r.replyQueue, // name
+		false,        // durable
+		false,        // delete when unused
+		true,         // exclusive
+		false,        // no-wait
+		nil,          // arguments
+	)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := r.channel().Consume(
+		q.Name, // queue
+		"",     // consumer
+		true,   // auto-ack
+		true,   // exclusive
+		false,  // no-local
+		false,  // no-wait
+		nil,    // args
+	)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		// ends when the connection is lost, the calls in flight run out of time then
+		for d := range deliveries {
+			r.deliver(d)
+		}
+	}()
+	return nil
+}
+
+// deliver passes the response to the call waiting for it, the late responses of the calls which gave up are dropped
+func (r *RabbitMQ) deliver(d amqp.Delivery) {
+	r.pendingMu.Lock()
+	reply, ok := r.pending[d.CorrelationId]
+	delete(r.pending, d.CorrelationId)
+	r.pendingMu.Unlock()
+
+	if ok {
+		reply <- d
+	}
+}
+
+// call sends the request to the queue and decodes the response with the same correlation id into resp.
+// It waits until ctx is done, for r.timeout at most if ctx has no deadline.
+func (r *RabbitMQ) call(ctx context.Context, queue string, req, resp any) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	id := strconv.FormatUint(r.lastID.Add(1), 10)
+	reply := make(chan amqp.Delivery, 1)
+	r.pendingMu.Lock()
+	r.pending[id] = reply
+	r.pendingMu.Unlock()
+	defer func() {
+		r.pendingMu.Lock()
+		delete(r.pending, id)
+		r.pendingMu.Unlock()
+	}()
+
+	err = r.channel().Publish(
+		"",    // exchange
+		queue, // routing key
+		false, // mandatory
+		false, // immediate
+		amqp.Publishing{
+			ContentType:   "application/json",
+			CorrelationId: id,
+			ReplyTo:       r.replyQueue,
+			Body:          body,
+			Headers:       r.headers(),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case d := <-reply:
+		return json.Unmarshal(d.Body, resp)
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return ErrTimeout
+		}
+		return ctx.Err()
+	}
+}
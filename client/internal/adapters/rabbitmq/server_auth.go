@@ -1,10 +1,8 @@
 package rabbitmq
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"github.com/streadway/amqp"
-	"time"
 )
 
 type loginRequest struct {
@@ -18,171 +16,75 @@ type registerRequest struct {
 }
 
 type loginResponse struct {
-	Err string `json:"error,omitempty"`
+	Token string `json:"token,omitempty"`
+	Err   string `json:"error,omitempty"`
 }
 
 type registerResponse struct {
-	Err string `json:"error,omitempty"`
+	Token string `json:"token,omitempty"`
+	Err   string `json:"error,omitempty"`
 }
 
-func (r *RabbitMQ) Login(login, password string) error {
-	q, err := r.ch.QueueDeclare(
-		"",    // name
-		false, // durable
-		false, // delete when unused
-		true,  // exclusive
-		false, // no-wait
-		nil,   // arguments
-	)
-	if err != nil {
-		return err
-	}
+type logoutRequest struct{}
 
-	req := loginRequest{
-		Username: login,
-		Password: password,
-	}
+type logoutResponse struct {
+	Err string `json:"error,omitempty"`
+}
 
-	body, err := json.Marshal(req)
-	if err != nil {
-		return err
-	}
+const ( // queue names
+	authLogin    = "auth.login"
+	authRegister = "auth.register"
+	authLogout   = "auth.logout"
+)
 
-	err = r.ch.Publish(
-		"",           // exchange
-		"auth.login", // routing key
-		false,        // mandatory
-		false,        // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-			ReplyTo:     q.Name,
-		},
-	)
+func (r *RabbitMQ) Login(login, password string) error {
+	var response loginResponse
+	err := r.call(context.Background(), authLogin, loginRequest{Username: login, Password: password}, &response)
 	if err != nil {
 		return err
 	}
-
-	msgs, err := r.ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
-
-	if err != nil {
-		return err
+	if response.Err != "" {
+		return errors.New(response.Err)
 	}
 
-	timer := time.NewTimer(r.timeout)
-
-	select {
-	case d, ok := <-msgs:
-		if !ok {
-			return errors.New("client playerLogin ch closed")
-		}
-		var response loginResponse
-		err = json.Unmarshal(d.Body, &response)
-		if err != nil {
-			return err
-		}
-		if response.Err != "" {
-			return errors.New(response.Err)
-		}
-
-		err := r.ch.Cancel(q.Name, false)
-		if err != nil {
-			return err
-		}
-
-		r.player1Login = login
-		r.initQueue()
-		return nil
-	case <-timer.C:
-		return errors.New("timeout")
-	}
+	r.player1Login = login
+	r.token = response.Token
+	return r.initQueue()
 }
 
 func (r *RabbitMQ) Register(login, password string) error {
-	q, err := r.ch.QueueDeclare(
-		"",    // name
-		false, // durable
-		false, // delete when unused
-		true,  // exclusive
-		false, // no-wait
-		nil,   // arguments
-	)
+	var response registerResponse
+	err := r.call(context.Background(), authRegister, registerRequest{Username: login, Password: password}, &response)
 	if err != nil {
 		return err
 	}
-
-	req := registerRequest{
-		Username: login,
-		Password: password,
+	if response.Err != "" {
+		return errors.New(response.Err)
 	}
 
-	body, err := json.Marshal(req)
-	if err != nil {
-		return err
-	}
+	r.player1Login = login
+	r.token = response.Token
+	return r.initQueue()
+}
 
-	err = r.ch.Publish(
-		"",              // exchange
-		"auth.register", // routing key
-		false,           // mandatory
-		false,           // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-			ReplyTo:     q.Name,
-		},
-	)
+// Logout ends the session on the server and removes the personal queue of the player
+func (r *RabbitMQ) Logout() error {
+	var response logoutResponse
+	err := r.call(context.Background(), authLogout, logoutRequest{}, &response)
 	if err != nil {
 		return err
 	}
+	if response.Err != "" {
+		return errors.New(response.Err)
+	}
 
-	msgs, err := r.ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
+	_, err = r.channel().QueueDelete(r.que.Name, false, false, false)
 	if err != nil {
 		return err
 	}
 
-	timer := time.NewTimer(r.timeout)
-
-	select {
-	case d, ok := <-msgs:
-		if !ok {
-			return errors.New("client register ch closed")
-		}
-
-		var response registerResponse
-		err = json.Unmarshal(d.Body, &response)
-		if err != nil {
-			return err
-		}
-		if response.Err != "" {
-			return errors.New(response.Err)
-		}
-
-		err = r.ch.Cancel(q.Name, false)
-		if err != nil {
-			return err
-		}
-
-		r.player1Login = login
-		r.initQueue()
-		return nil
-	case <-timer.C:
-		return errors.New("timeout")
-	}
+	r.player1Login = ""
+	r.player2Login = ""
+	r.token = ""
+	return nil
 }
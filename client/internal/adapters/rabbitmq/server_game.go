@@ -3,24 +3,17 @@ package rabbitmq
 import (
 	"battlship/internal/service/game/domain"
 	"context"
-	"encoding/json"
 	"errors"
-	"github.com/streadway/amqp"
 	"time"
 )
 
-type gameCreateRequest struct {
-	UserName string `json:"user_name"`
-}
+type gameCreateRequest struct{}
 
 type gameJoinRequest struct {
 	CreatorUserName string `json:"creator_user_name"`
-	JoiningUserName string `json:"joining_user_name"`
 }
 
-type gameDelRequest struct {
-	UserName string `json:"user_name"`
-}
+type gameDelRequest struct{}
 
 type gameDelResponse struct {
 	Err string `json:"error,omitempty"`
@@ -62,6 +55,98 @@ type gameResultResponse struct {
 	Err string `json:"error,omitempty"`
 }
 
+type getHistoryRequest struct {
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+}
+
+type matchInfo struct {
+	ID                int64     `json:"id"`
+	Winner            string    `json:"winner"`
+	Loser             string    `json:"loser"`
+	StartedAt         time.Time `json:"started_at"`
+	EndedAt           time.Time `json:"ended_at"`
+	DurationSec       int       `json:"duration_sec"`
+	Shots             int       `json:"shots"`
+	WinnerRatingDelta float64   `json:"winner_rating_delta"`
+	LoserRatingDelta  float64   `json:"loser_rating_delta"`
+	EndReason         string    `json:"end_reason"`
+}
+
+func (m matchInfo) toDomain() domain.Match {
+	return domain.Match{
+		ID:                m.ID,
+		Winner:            m.Winner,
+		Loser:             m.Loser,
+		StartedAt:         m.StartedAt,
+		EndedAt:           m.EndedAt,
+		Duration:          time.Duration(m.DurationSec) * time.Second,
+		Shots:             m.Shots,
+		WinnerRatingDelta: m.WinnerRatingDelta,
+		LoserRatingDelta:  m.LoserRatingDelta,
+		EndReason:         m.EndReason,
+	}
+}
+
+type getHistoryResponse struct {
+	Matches []matchInfo `json:"matches"`
+	Total   int         `json:"total"`
+	Err     string      `json:"error,omitempty"`
+}
+
+type getLeaderboardRequest struct {
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+}
+
+type leaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	UserName string `json:"user_name"`
+	Rating   int    `json:"rating"`
+	Wins     int    `json:"wins"`
+	Losses   int    `json:"losses"`
+}
+
+type getLeaderboardResponse struct {
+	Entries []leaderboardEntry `json:"entries"`
+	Total   int                `json:"total"`
+	MyRank  int                `json:"my_rank"`
+	Err     string             `json:"error,omitempty"`
+}
+
+type quickMatchRequest struct {
+	Cancel bool `json:"cancel,omitempty"`
+}
+
+type quickMatchResponse struct {
+	Opponent       string `json:"opponent,omitempty"`
+	OpponentRating int    `json:"opponent_rating,omitempty"`
+	Err            string `json:"error,omitempty"`
+}
+
+type getReplayRequest struct {
+	MatchID int64 `json:"match_id"`
+}
+
+type replayEvent struct {
+	Seq     int       `json:"seq"`
+	Type    string    `json:"type"`
+	Player  string    `json:"player"`
+	X       int       `json:"x,omitempty"`
+	Y       int       `json:"y,omitempty"`
+	Hit     bool      `json:"hit,omitempty"`
+	Destroy bool      `json:"destroy,omitempty"`
+	Fleet   [][]Point `json:"fleet,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+type getReplayResponse struct {
+	Match  matchInfo     `json:"match"`
+	Events []replayEvent `json:"events"`
+	Err    string        `json:"error,omitempty"`
+}
+
 const ( //queue names
 	gameCreate        = "game.create"
 	gameJoin          = "game.join"
@@ -69,275 +154,201 @@ const ( //queue names
 	saveGameResult    = "game.save_result"
 	getUserStat       = "game.get_user_stat"
 	gameDel           = "game.del"
+	getHistory        = "game.get_history"
+	getLeaderboard    = "game.get_leaderboard"
+	quickMatch        = "game.quick_match"
+	getReplay         = "game.get_replay"
 )
 
+// CreateGame waits until another player joins the game, the game is deleted if ctx is done first
 func (r *RabbitMQ) CreateGame(ctx context.Context) (user2 string, err error) {
-
-	req := gameCreateRequest{
-		UserName: r.player1Login,
-	}
-	body, err := json.Marshal(req)
+	var response gameCreateResponse
+	err = r.call(ctx, gameCreate, gameCreateRequest{}, &response)
 	if err != nil {
+		if delErr := r.DelGame(); delErr != nil {
+			return "", delErr
+		}
 		return "", err
 	}
+	if response.Err != "" {
+		return "", errors.New(response.Err)
+	}
+	r.player2Login = response.User2
+	return response.User2, nil
+}
 
-	err = r.ch.Publish(
-		"",         // exchange
-		gameCreate, // routing key
-		false,      // mandatory
-		false,      // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-			ReplyTo:     r.que.Name,
-		},
-	)
+// QuickMatch waits until the server pairs the player with an opponent of a close rating
+func (r *RabbitMQ) QuickMatch(ctx context.Context) (user2 string, err error) {
+	var response quickMatchResponse
+	err = r.call(ctx, quickMatch, quickMatchRequest{}, &response)
 	if err != nil {
+		if cancelErr := r.CancelQuickMatch(); cancelErr != nil {
+			return "", cancelErr
+		}
 		return "", err
 	}
-
-	timer := time.NewTimer(r.timeout)
-	select {
-	case d := <-r.msgs:
-		var response gameCreateResponse
-		err = json.Unmarshal(d.Body, &response)
-		if err != nil {
-			return "", err
-		}
-		if response.Err != "" {
-			return "", errors.New(response.Err)
-		}
-		r.player2Login = response.User2
-		return response.User2, nil
-	case <-timer.C:
-		err = r.DelGame()
-		if err != nil {
-			return "", err
-		}
-		return "", errors.New("timeout")
-	case <-ctx.Done(): // cancel create game
-		err = r.DelGame()
-		if err != nil {
-			return "", err
-		}
-		return "", errors.New("context done")
+	if response.Err != "" {
+		return "", errors.New(response.Err)
 	}
+	r.player2Login = response.Opponent
+	return response.Opponent, nil
 }
 
-func (r *RabbitMQ) DelGame() error {
-
-	req := gameDelRequest{
-		UserName: r.player1Login,
-	}
-	body, err := json.Marshal(req)
+// CancelQuickMatch takes the player out of the quick match queue
+func (r *RabbitMQ) CancelQuickMatch() error {
+	var response quickMatchResponse
+	err := r.call(context.Background(), quickMatch, quickMatchRequest{Cancel: true}, &response)
 	if err != nil {
 		return err
 	}
+	if response.Err != "" {
+		return errors.New(response.Err)
+	}
+	return nil
+}
 
-	err = r.ch.Publish(
-		"",      // exchange
-		gameDel, // routing key
-		false,   // mandatory
-		false,   // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-			ReplyTo:     r.que.Name,
-		},
-	)
+func (r *RabbitMQ) DelGame() error {
+	var response gameDelResponse
+	err := r.call(context.Background(), gameDel, gameDelRequest{}, &response)
 	if err != nil {
 		return err
 	}
-
-	timer := time.NewTimer(r.timeout)
-	select {
-	case d := <-r.msgs:
-		var response gameDelResponse
-		err = json.Unmarshal(d.Body, &response)
-		if err != nil {
-			return err
-		}
-		if response.Err != "" {
-			return errors.New(response.Err)
-		}
-		return nil
-	case <-timer.C:
-		return errors.New("timeout")
+	if response.Err != "" {
+		return errors.New(response.Err)
 	}
+	return nil
 }
 
 func (r *RabbitMQ) GetUserStat(username string) (domain.Statistics, error) {
-
-	req := getStatRequest{
-		UserName: username,
-	}
-
-	body, err := json.Marshal(req)
+	var response getStatResponse
+	err := r.call(context.Background(), getUserStat, getStatRequest{UserName: username}, &response)
 	if err != nil {
 		return domain.Statistics{}, err
 	}
+	if response.Err != "" {
+		return domain.Statistics{}, errors.New(response.Err)
+	}
+	return domain.Statistics{
+		Rating: response.Rating,
+		Wins:   response.Wins,
+		Losses: response.Losses,
+	}, nil
+}
 
-	err = r.ch.Publish(
-		"",          // exchange
-		getUserStat, // routing key
-		false,       // mandatory
-		false,       // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-			ReplyTo:     r.que.Name,
-		},
-	)
+func (r *RabbitMQ) JoinGame(creatorUserName string) error {
+	var response gameJoinResponse
+	err := r.call(context.Background(), gameJoin, gameJoinRequest{CreatorUserName: creatorUserName}, &response)
 	if err != nil {
-		return domain.Statistics{}, err
+		return err
 	}
-
-	//timer := time.NewTimer(r.timeout)
-	select {
-	case d := <-r.msgs:
-		var response getStatResponse
-		err = json.Unmarshal(d.Body, &response)
-		if err != nil {
-			return domain.Statistics{}, err
-		}
-		if response.Err != "" {
-			return domain.Statistics{}, errors.New(response.Err)
-		}
-		return domain.Statistics{
-			Rating: response.Rating,
-			Wins:   response.Wins,
-			Losses: response.Losses,
-		}, nil
-		//case <-timer.C:
-		//	return domain.Statistics{}, errors.New("timeout")
+	if response.Err != "" {
+		return errors.New(response.Err)
 	}
+	r.player2Login = creatorUserName
+	return nil
 }
 
-func (r *RabbitMQ) JoinGame(creatorUserName string) error {
-
-	req := gameJoinRequest{
-		CreatorUserName: creatorUserName,
-		JoiningUserName: r.player1Login,
-	}
-	body, err := json.Marshal(req)
+func (r *RabbitMQ) GetAvailableGames() ([]string, error) {
+	var response getAvailableGamesResponse
+	err := r.call(context.Background(), getAvailableGames, getAvailableGamesRequest{}, &response)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if response.Err != "" {
+		return nil, errors.New(response.Err)
 	}
+	return response.Games, nil
+}
 
-	err = r.ch.Publish(
-		"",       // exchange
-		gameJoin, // routing key
-		false,    // mandatory
-		false,    // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-			ReplyTo:     r.que.Name,
-		},
-	)
+func (r *RabbitMQ) SaveGameResult(winner, loser string) error {
+	var response gameResultResponse
+	err := r.call(context.Background(), saveGameResult, gameResultRequest{Winner: winner, Loser: loser}, &response)
 	if err != nil {
 		return err
 	}
-
-	timer := time.NewTimer(r.timeout)
-	select {
-	case d := <-r.msgs:
-		var response gameJoinResponse
-		err = json.Unmarshal(d.Body, &response)
-		if err != nil {
-			return err
-		}
-		if response.Err != "" {
-			return errors.New(response.Err)
-		}
-		r.player2Login = creatorUserName
-		return nil
-	case <-timer.C:
-		return errors.New("timeout")
+	if response.Err != "" {
+		return errors.New(response.Err)
 	}
+	return nil
 }
 
-func (r *RabbitMQ) GetAvailableGames() ([]string, error) {
-
-	req := getAvailableGamesRequest{}
-	body, err := json.Marshal(req)
+// GetHistory returns a page of the finished games of the player, the latest first, and the number of all of them
+func (r *RabbitMQ) GetHistory(page, pageSize int) ([]domain.Match, int, error) {
+	var response getHistoryResponse
+	err := r.call(context.Background(), getHistory, getHistoryRequest{Page: page, PageSize: pageSize}, &response)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	if response.Err != "" {
+		return nil, 0, errors.New(response.Err)
 	}
+	matches := make([]domain.Match, 0, len(response.Matches))
+	for _, m := range response.Matches {
+		matches = append(matches, m.toDomain())
+	}
+	return matches, response.Total, nil
+}
 
-	err = r.ch.Publish(
-		"",                // exchange
-		getAvailableGames, // routing key
-		false,             // mandatory
-		false,             // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-			ReplyTo:     r.que.Name,
-		},
-	)
+// GetReplay returns the finished game with the ordered log of its events
+func (r *RabbitMQ) GetReplay(matchID int64) (domain.Replay, error) {
+	var response getReplayResponse
+	err := r.call(context.Background(), getReplay, getReplayRequest{MatchID: matchID}, &response)
 	if err != nil {
-		return nil, err
+		return domain.Replay{}, err
 	}
-
-	timer := time.NewTimer(r.timeout)
-	select {
-	case d := <-r.msgs:
-		var response getAvailableGamesResponse
-		err = json.Unmarshal(d.Body, &response)
-		if err != nil {
-			return nil, err
-		}
-		if response.Err != "" {
-			return nil, errors.New(response.Err)
+	if response.Err != "" {
+		return domain.Replay{}, errors.New(response.Err)
+	}
+	replay := domain.Replay{
+		Match:  response.Match.toDomain(),
+		Events: make([]domain.ReplayEvent, 0, len(response.Events)),
+	}
+	for _, e := range response.Events {
+		var fleet [][]domain.Cell
+		for _, ship := range e.Fleet {
+			cells := make([]domain.Cell, 0, len(ship))
+			for _, p := range ship {
+				cells = append(cells, domain.Cell{X: p.X, Y: p.Y})
+			}
+			fleet = append(fleet, cells)
 		}
-		return response.Games, nil
-	case <-timer.C:
-		return nil, errors.New("timeout")
+		replay.Events = append(replay.Events, domain.ReplayEvent{
+			Seq:     e.Seq,
+			Type:    e.Type,
+			Player:  e.Player,
+			X:       e.X,
+			Y:       e.Y,
+			Hit:     e.Hit,
+			Destroy: e.Destroy,
+			Fleet:   fleet,
+			Reason:  e.Reason,
+			At:      e.At,
+		})
 	}
+	return replay, nil
 }
 
-func (r *RabbitMQ) SaveGameResult(winner, loser string) error {
-
-	req := gameResultRequest{
-		Winner: winner,
-		Loser:  loser,
-	}
-	body, err := json.Marshal(req)
+// GetLeaderboard returns a page of the leaderboard, the number of ranked players and the rank of the player
+func (r *RabbitMQ) GetLeaderboard(page, pageSize int) ([]domain.LeaderboardEntry, int, int, error) {
+	var response getLeaderboardResponse
+	err := r.call(context.Background(), getLeaderboard, getLeaderboardRequest{Page: page, PageSize: pageSize}, &response)
 	if err != nil {
-		return err
+		return nil, 0, 0, err
 	}
-
-	err = r.ch.Publish(
-		"",             // exchange
-		saveGameResult, // routing key
-		false,          // mandatory
-		false,          // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-			ReplyTo:     r.que.Name,
-		},
-	)
-	if err != nil {
-		return err
+	if response.Err != "" {
+		return nil, 0, 0, errors.New(response.Err)
 	}
-
-	timer := time.NewTimer(r.timeout)
-	select {
-	case d := <-r.msgs:
-		var response gameResultResponse
-		err = json.Unmarshal(d.Body, &response)
-		if err != nil {
-			return err
-		}
-		if response.Err != "" {
-			return errors.New(response.Err)
-		}
-		return nil
-	case <-timer.C:
-		return errors.New("timeout")
+	entries := make([]domain.LeaderboardEntry, 0, len(response.Entries))
+	for _, e := range response.Entries {
+		entries = append(entries, domain.LeaderboardEntry{
+			Rank:     e.Rank,
+			UserName: e.UserName,
+			Rating:   e.Rating,
+			Wins:     e.Wins,
+			Losses:   e.Losses,
+		})
 	}
+	return entries, response.Total, response.MyRank, nil
 }
 
 func (r *RabbitMQ) GetOpponentName() (string, error) {
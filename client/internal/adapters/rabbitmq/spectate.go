@@ -0,0 +1,179 @@
+package rabbitmq
+
+import (
+	"battlship/internal/service/game/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// spectateExchange is the topic exchange the server publishes the events of every running game to,
+// the routing key is the id of the game
+const spectateExchange = "game.spectate"
+
+const ( // queue names
+	listLive  = "game.list_live"
+	gameWatch = "game.watch"
+)
+
+type liveGame struct {
+	ID        string    `json:"id"`
+	User1     string    `json:"user1"`
+	User2     string    `json:"user2"`
+	Started   bool      `json:"started"`
+	Shots     int       `json:"shots"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+type listLiveRequest struct{}
+
+type listLiveResponse struct {
+	Games []liveGame `json:"games"`
+	Err   string     `json:"error,omitempty"`
+}
+
+type watchRequest struct {
+	GameID string `json:"game_id"`
+}
+
+// WatchState is the state of the watched game when the spectator joined
+type WatchState struct {
+	Game struct {
+		User1 string `json:"user1"`
+		User2 string `json:"user2"`
+	} `json:"game"`
+	Turn  string            `json:"turn,omitempty"`
+	Shots map[string][]Shot `json:"shots,omitempty"` // user name -> shots at the sea of the user
+	Err   string            `json:"error,omitempty"`
+}
+
+// SpectatorMessage structure:
+// Ready { Attacker } - the battle began, Attacker shoots first;
+// Result { Attacker, X, Y, Hit, Destroy };
+// End { Attacker, X, Y, Hit, Destroy, Winner, Reason, Fleets }
+type SpectatorMessage struct {
+	Type     MessageType          `json:"type"`
+	GameID   string               `json:"game_id"`
+	Attacker string               `json:"attacker,omitempty"`
+	X        int                  `json:"x,omitempty"`
+	Y        int                  `json:"y,omitempty"`
+	Hit      bool                 `json:"hit,omitempty"`
+	Destroy  bool                 `json:"destroy,omitempty"`
+	Winner   string               `json:"winner,omitempty"`
+	Reason   string               `json:"reason,omitempty"`
+	Fleets   map[string][][]Point `json:"fleets,omitempty"` // user name -> ships, revealed at the end
+}
+
+func (r *RabbitMQ) ListLiveGames() ([]domain.LiveGame, error) {
+	var response listLiveResponse
+	err := r.call(context.Background(), listLive, listLiveRequest{}, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Err != "" {
+		return nil, errors.New(response.Err)
+	}
+	games := make([]domain.LiveGame, 0, len(response.Games))
+	for _, g := range response.Games {
+		games = append(games, domain.LiveGame{
+			ID:        g.ID,
+			User1:     g.User1,
+			User2:     g.User2,
+			Started:   g.Started,
+			Shots:     g.Shots,
+			StartedAt: g.StartedAt,
+		})
+	}
+	return games, nil
+}
+
+// Watch subscribes to the events of the game and returns its state at the moment of the subscription.
+// stop must be called when the spectator leaves.
+func (r *RabbitMQ) Watch(gameID string) (state WatchState, msgs <-chan SpectatorMessage, stop func(), err error) {
+	err = r.channel().ExchangeDeclare(
+		spectateExchange, // name
+		"topic",          // type
+		false,            // durable
+		false,            // auto-deleted
+		false,            // internal
+		false,            // no-wait
+		nil,              // arguments
+	)
+	if err != nil {
+		return WatchState{}, nil, nil, err
+	}
+
+	q, err := r.channel().QueueDeclare(
+		"",    // name
+		false, // durable
+		true,  // delete when unused
+		true,  // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+	if err != nil {
+		return WatchState{}, nil, nil, err
+	}
+
+	err = r.channel().QueueBind(
+		q.Name,           // queue name
+		gameID,           // routing key
+		spectateExchange, // exchange
+		false,            // no-wait
+		nil,              // arguments
+	)
+	if err != nil {
+		return WatchState{}, nil, nil, err
+	}
+
+	deliveries, err := r.channel().Consume(
+		q.Name, // queue
+		q.Name, // consumer
+		true,   // auto-ack
+		true,   // exclusive
+		false,  // no-local
+		false,  // no-wait
+		nil,    // args
+	)
+	if err != nil {
+		return WatchState{}, nil, nil, err
+	}
+	stop = func() {
+		_ = r.channel().Cancel(q.Name, false)
+		_, _ = r.channel().QueueDelete(q.Name, false, false, false)
+	}
+
+	// the state is asked for after the subscription, so no shot is missed in between
+	state, err = r.watchState(gameID)
+	if err != nil {
+		stop()
+		return WatchState{}, nil, nil, err
+	}
+
+	out := make(chan SpectatorMessage)
+	go func() {
+		for d := range deliveries {
+			var msg SpectatorMessage
+			err := json.Unmarshal(d.Body, &msg)
+			if err != nil {
+				continue
+			}
+			out <- msg
+		}
+		close(out)
+	}()
+	return state, out, stop, nil
+}
+
+func (r *RabbitMQ) watchState(gameID string) (WatchState, error) {
+	var response WatchState
+	err := r.call(context.Background(), gameWatch, watchRequest{GameID: gameID}, &response)
+	if err != nil {
+		return WatchState{}, err
+	}
+	if response.Err != "" {
+		return WatchState{}, errors.New(response.Err)
+	}
+	return response, nil
+}
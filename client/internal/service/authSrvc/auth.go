@@ -3,6 +3,7 @@ package authSrvc
 type authMQ interface {
 	Login(login, password string) error
 	Register(login, password string) error
+	Logout() error
 }
 
 type Auth struct {
@@ -38,6 +39,11 @@ func (a *Auth) GerUserLogin() string {
 	return a.login
 }
 
-func (a *Auth) Logout() {
+func (a *Auth) Logout() error {
+	err := a.mq.Logout()
+	if err != nil {
+		return err
+	}
 	a.login = ""
+	return nil
 }
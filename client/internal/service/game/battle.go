@@ -3,7 +3,6 @@ package gameSrvs
 import (
 	"battlship/internal/adapters/rabbitmq"
 	"errors"
-	"fmt"
 	"time"
 )
 
@@ -11,36 +10,115 @@ var (
 	InternalError = errors.New("internal error")
 )
 
+// GameOverError is returned by Ready when the game ends before the battle begins,
+// e.g. the opponent left or the game was cancelled
+type GameOverError struct {
+	MsgToUser string // the outcome of the game, see Won and Lost
+}
+
+func (e *GameOverError) Error() string {
+	return e.MsgToUser
+}
+
 type battleMQ interface {
 	GetterMessages() (<-chan rabbitmq.Message, error)
 	SendMessage(msg rabbitmq.Message) error
+	SendHeartbeat() error
 }
 
+// heartbeatInterval must be well below the heartbeat timeout of the server
+const heartbeatInterval = 10 * time.Second
+
+// StartBattle prepares the battle of the game just started,
+// the battle messages left from the previous battle are dropped
 func (b *BattleShip) StartBattle() error {
-	msgs, err := b.mq.GetterMessages()
+	err := b.startBattle()
 	if err != nil {
 		return err
 	}
-	b.opponentMsgs = msgs
+	b.dropStale()
 	return nil
 }
 
-func (b *BattleShip) Ready() (iFirst bool, err error) {
-	ticker := time.NewTimer(2 * time.Second)
-	select {
-	case msg := <-b.opponentMsgs:
-		fmt.Println("Get message: ", msg)
-		if msg.Type != rabbitmq.Ready {
-			return false, InternalError
+// startBattle starts reading the messages of the server, they are read by the same goroutines for all battles
+func (b *BattleShip) startBattle() error {
+	b.reset()
+	if b.opponentMsgs == nil {
+		msgs, err := b.mq.GetterMessages()
+		if err != nil {
+			return err
+		}
+		b.opponentMsgs, b.chatMsgs = splitChat(msgs)
+	}
+
+	b.stopHeartbeats()
+	b.heartbeatsDone = make(chan struct{})
+	go b.heartbeats(b.heartbeatsDone)
+	return nil
+}
+
+// dropStale drops the battle messages that came after the end of the previous battle, e.g. its cancellation
+func (b *BattleShip) dropStale() {
+	for {
+		select {
+		case _, ok := <-b.opponentMsgs:
+			if !ok {
+				return
+			}
+		default:
+			return
 		}
-		return false, nil
-	case <-ticker.C:
-		err = b.mq.SendMessage(rabbitmq.Message{Type: rabbitmq.Ready})
+	}
+}
+
+// heartbeats keeps the player in the game until done is closed
+func (b *BattleShip) heartbeats(done <-chan struct{}) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			_ = b.mq.SendHeartbeat() // a missed heartbeat is made up by the next one
+		}
+	}
+}
+
+func (b *BattleShip) stopHeartbeats() {
+	if b.heartbeatsDone != nil {
+		close(b.heartbeatsDone)
+		b.heartbeatsDone = nil
+	}
+}
+
+// Ready sends the placed fleet to the server and waits until the opponent's fleet is placed too.
+// A resumed battle is ready at once and iFirst tells whether the player shoots next.
+func (b *BattleShip) Ready() (iFirst bool, err error) {
+	if b.started { // resumed battle
+		return b.myTurn, nil
+	}
+	if !b.fleetSent {
+		err = b.mq.SendMessage(rabbitmq.Message{Type: rabbitmq.Ready, Ships: b.fleet})
 		if err != nil {
 			return false, InternalError
 		}
-		return true, nil
+		b.fleetSent = true
+	}
+	msg, ok := <-b.opponentMsgs
+	if ok && msg.Type == rabbitmq.End {
+		return false, &GameOverError{MsgToUser: b.endMessage(msg)}
+	}
+	if !ok || msg.Type != rabbitmq.Ready {
+		b.stopHeartbeats()
+		return false, InternalError
+	}
+	if msg.Err != "" {
+		b.stopHeartbeats()
+		return false, errors.New(msg.Err)
 	}
+	return msg.First, nil
 }
 
 func (b *BattleShip) Attack(x, y int) (msgToUser string, err error) {
@@ -53,7 +131,13 @@ func (b *BattleShip) Attack(x, y int) (msgToUser string, err error) {
 	if err != nil {
 		return "", InternalError
 	}
-	resultMsg := <-b.opponentMsgs
+	resultMsg, ok := <-b.opponentMsgs
+	if !ok {
+		return "", InternalError
+	}
+	if resultMsg.Err != "" {
+		return "", errors.New(resultMsg.Err)
+	}
 	switch resultMsg.Type {
 	case rabbitmq.Result:
 		b.markHitOrMiss(x, y, resultMsg.Hit, resultMsg.Destroy, false)
@@ -65,7 +149,10 @@ func (b *BattleShip) Attack(x, y int) (msgToUser string, err error) {
 			msgToUser = "You missed"
 		}
 	case rabbitmq.End:
-		msgToUser = Win
+		if resultMsg.Reason == rabbitmq.AllSunk {
+			b.markHitOrMiss(x, y, resultMsg.Hit, resultMsg.Destroy, false)
+		}
+		msgToUser = b.endMessage(resultMsg)
 	default:
 		return "", InternalError
 	}
@@ -73,42 +160,65 @@ func (b *BattleShip) Attack(x, y int) (msgToUser string, err error) {
 }
 
 const (
-	Win  = "You win!"
-	Lose = "You lose"
+	Win              = "You win!"
+	Lose             = "You lose"
+	OpponentLeft     = "The opponent left the game. You win!"
+	OpponentTimedOut = "The opponent ran out of time. You win!"
+	TimedOut         = "You ran out of time. You lose"
+	Cancelled        = "The game is cancelled before the battle, nobody wins"
 )
 
+// Won reports whether the message of the finished game tells the player won
+func Won(msgToUser string) bool {
+	return msgToUser == Win || msgToUser == OpponentLeft || msgToUser == OpponentTimedOut
+}
+
+// Lost reports whether the message tells the game is over and the player did not win
+func Lost(msgToUser string) bool {
+	return msgToUser == Lose || msgToUser == TimedOut || msgToUser == Cancelled
+}
+
+// endMessage tells the player the outcome of the game
+func (b *BattleShip) endMessage(msg rabbitmq.Message) string {
+	b.stopHeartbeats()
+	opponent, _ := b.mq.GetOpponentName()
+	switch {
+	case msg.Winner == "":
+		return Cancelled
+	case msg.Winner == opponent && msg.Reason == rabbitmq.TurnTimeout:
+		return TimedOut
+	case msg.Winner == opponent:
+		return Lose
+	case msg.Reason == rabbitmq.Forfeit, msg.Reason == rabbitmq.Abandoned:
+		return OpponentLeft
+	case msg.Reason == rabbitmq.TurnTimeout:
+		return OpponentTimedOut
+	}
+	return Win
+}
+
+// Defend waits for the opponent's shot resolved by the server
 func (b *BattleShip) Defend() (msgToUser string, err error) {
-	msg := <-b.opponentMsgs
-	if msg.Type != rabbitmq.Attack {
+	msg, ok := <-b.opponentMsgs
+	if !ok {
 		return "", InternalError
 	}
-	x, y := msg.X, msg.Y
-	hit, destroy := b.hit(x, y)
-
-	b.markHitOrMiss(x, y, hit, destroy, true)
-
-	var ansMsg rabbitmq.Message
-	if b.AllShipsDestroyed() {
-		ansMsg = rabbitmq.Message{
-			Type: rabbitmq.End,
-		}
-		msgToUser = Lose
-	} else {
-		ansMsg = rabbitmq.Message{
-			Type:    rabbitmq.Result,
-			Hit:     hit,
-			Destroy: destroy,
-		}
-		if destroy {
+	switch msg.Type {
+	case rabbitmq.Result:
+		b.markHitOrMiss(msg.X, msg.Y, msg.Hit, msg.Destroy, true)
+		if msg.Destroy {
 			msgToUser = "The enemy destroyed the ship"
-		} else if hit {
+		} else if msg.Hit {
 			msgToUser = "The enemy got hit"
 		} else {
 			msgToUser = "The enemy missed!"
 		}
-	}
-	err = b.mq.SendMessage(ansMsg)
-	if err != nil {
+	case rabbitmq.End:
+		if msg.Reason == rabbitmq.AllSunk {
+			b.markHitOrMiss(msg.X, msg.Y, msg.Hit, msg.Destroy, true)
+		}
+		msgToUser = b.endMessage(msg)
+	default:
 		return "", InternalError
 	}
 	return msgToUser, nil
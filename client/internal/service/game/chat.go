@@ -0,0 +1,48 @@
+package gameSrvs
+
+import "battlship/internal/adapters/rabbitmq"
+
+// ChatMessage is a chat message from the opponent, or the reason the server rejected the message of the player
+type ChatMessage struct {
+	From string
+	Text string
+	Err  string
+}
+
+// chatBuffer is how many chat messages wait for the UI before new ones are dropped
+const chatBuffer = 16
+
+// splitChat separates the chat messages from the messages of the battle
+func splitChat(msgs <-chan rabbitmq.Message) (<-chan rabbitmq.Message, <-chan ChatMessage) {
+	battle := make(chan rabbitmq.Message)
+	chat := make(chan ChatMessage, chatBuffer)
+	go func() {
+		for msg := range msgs {
+			if msg.Type != rabbitmq.Chat {
+				battle <- msg
+				continue
+			}
+			select {
+			case chat <- ChatMessage{From: msg.From, Text: msg.Text, Err: msg.Err}:
+			default: // nobody reads the chat, the battle must go on
+			}
+		}
+		close(battle)
+		close(chat)
+	}()
+	return battle, chat
+}
+
+// SendChat sends the chat message to the opponent through the server
+func (b *BattleShip) SendChat(text string) error {
+	err := b.mq.SendMessage(rabbitmq.Message{Type: rabbitmq.Chat, Text: text})
+	if err != nil {
+		return InternalError
+	}
+	return nil
+}
+
+// ChatMessages returns the chat messages of the current battle
+func (b *BattleShip) ChatMessages() <-chan ChatMessage {
+	return b.chatMsgs
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "fmt"
+
+// LeaderboardEntry is a row of the leaderboard
+type LeaderboardEntry struct {
+	Rank     int
+	UserName string
+	Rating   int
+	Wins     int
+	Losses   int
+}
+
+func (e LeaderboardEntry) String() string {
+	return fmt.Sprintf("%4d. %-20s Rating: %d, Wins: %d, Losses: %d", e.Rank, e.UserName, e.Rating, e.Wins, e.Losses)
+}
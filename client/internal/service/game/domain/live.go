@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// LiveGame is a running game that can be watched
+type LiveGame struct {
+	ID        string
+	User1     string
+	User2     string
+	Started   bool
+	Shots     int
+	StartedAt time.Time
+}
+
+func (g LiveGame) String() string {
+	if !g.Started {
+		return fmt.Sprintf("%s vs %s  (placing ships)", g.User1, g.User2)
+	}
+	return fmt.Sprintf("%s vs %s  (%d shots, %v)", g.User1, g.User2, g.Shots, time.Since(g.StartedAt).Round(time.Second))
+}
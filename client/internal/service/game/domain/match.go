@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Match is a finished game from the history of a player
+type Match struct {
+	ID                int64
+	Winner            string
+	Loser             string
+	StartedAt         time.Time
+	EndedAt           time.Time
+	Duration          time.Duration
+	Shots             int
+	WinnerRatingDelta float64
+	LoserRatingDelta  float64
+	EndReason         string
+}
+
+func (m Match) String() string {
+	return fmt.Sprintf("%s  %s beat %s  (%d shots, %v, %s)",
+		m.EndedAt.Local().Format("2006-01-02 15:04"), m.Winner, m.Loser, m.Shots, m.Duration, m.EndReason)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// replay event types
+const (
+	ReplayFleet = "fleet"
+	ReplayShot  = "shot"
+	ReplayEnd   = "end"
+)
+
+type Cell struct {
+	X int
+	Y int
+}
+
+// ReplayEvent is an entry of the ordered log of a finished game.
+// Player is the owner of the fleet, the attacker or the winner depending on the type.
+type ReplayEvent struct {
+	Seq     int
+	Type    string
+	Player  string
+	X       int
+	Y       int
+	Hit     bool
+	Destroy bool
+	Fleet   [][]Cell
+	Reason  string
+	At      time.Time
+}
+
+// Replay is a finished game with the log of its events
+type Replay struct {
+	Match  Match
+	Events []ReplayEvent
+}
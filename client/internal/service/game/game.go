@@ -10,6 +10,8 @@ import (
 type gameMQ interface {
 	serverMQ
 	battleMQ
+	resumeMQ
+	spectateMQ
 }
 
 const seaSize = 10
@@ -18,30 +20,42 @@ type BattleShip struct {
 	mySea        [seaSize][seaSize]SeaCell
 	opponentSea  [seaSize][seaSize]SeaCell
 	ships        [4]int
+	fleet        [][]rabbitmq.Point // cells of the placed ships, sent to the server
 	mq           gameMQ
 	opponentMsgs <-chan rabbitmq.Message
+	chatMsgs     <-chan ChatMessage
+
+	heartbeatsDone chan struct{} // closed to stop the heartbeats of the current battle
+
+	resumeState *rabbitmq.GameState // the unfinished game found after login
+	fleetSent   bool                // the server already has the fleet of the resumed game
+	started     bool                // the resumed battle is already going on
+	myTurn      bool                // the player shoots next in the resumed battle
 }
 
 var maxShips = [4]int{4, 3, 2, 1}
 
 func New(mq gameMQ) *BattleShip {
-	emptySea := [seaSize][seaSize]SeaCell{}
-	for i := 0; i < seaSize; i++ {
-		for j := 0; j < seaSize; j++ {
-			emptySea[i][j] = emptyCell
-		}
+	b := &BattleShip{
+		mq: mq,
 	}
-	unknownSea := [seaSize][seaSize]SeaCell{}
+	b.reset()
+	return b
+}
+
+// reset clears both seas before a new game
+func (b *BattleShip) reset() {
 	for i := 0; i < seaSize; i++ {
 		for j := 0; j < seaSize; j++ {
-			unknownSea[i][j] = unknownCell
+			b.mySea[i][j] = emptyCell
+			b.opponentSea[i][j] = unknownCell
 		}
 	}
-	return &BattleShip{
-		mySea:       emptySea,
-		opponentSea: unknownSea,
-		mq:          mq,
-	}
+	b.ships = [4]int{}
+	b.fleet = nil
+	b.fleetSent = false
+	b.started = false
+	b.myTurn = false
 }
 
 type SeaCell rune
@@ -81,13 +95,17 @@ func (b *BattleShip) StringMap(myMap bool) [10]string {
 		fmt.Println("Print opponent sea: ")
 		sea = &b.opponentSea
 	}
+	return seaStrings(sea)
+}
+
+func seaStrings(sea *[seaSize][seaSize]SeaCell) [seaSize]string {
 	var seaMap [seaSize]string
 	for i := 0; i < seaSize; i++ {
 		for j := 0; j < seaSize; j++ {
 			if rune(sea[i][j]) >= '0' && rune(sea[i][j]) <= '9' { // == shipCell
 				seaMap[i] += string(shipCell)
 			} else {
-				seaMap[i] += fmt.Sprint(string(b.mySea[i][j]))
+				seaMap[i] += fmt.Sprint(string(sea[i][j]))
 			}
 			if j != seaSize-1 {
 				seaMap[i] += " "
@@ -105,24 +123,30 @@ func (b *BattleShip) PlaceShip(x, y int, sType ShipType, direction ShipDirection
 		return errors.New("fields is already occupied")
 	}
 
+	var cells []rabbitmq.Point
 	switch direction {
 	case up:
 		for i := y; i > y-(int(sType)+1); i-- {
 			b.mySea[i][x] = SeaCell(strconv.Itoa(int(sType))[0])
+			cells = append(cells, rabbitmq.Point{X: x, Y: i})
 		}
 	case down:
 		for i := y; i < y+int(sType)+1; i++ {
 			b.mySea[i][x] = SeaCell(strconv.Itoa(int(sType))[0])
+			cells = append(cells, rabbitmq.Point{X: x, Y: i})
 		}
 	case right:
 		for j := x; j < x+int(sType)+1; j++ {
 			b.mySea[y][j] = SeaCell(strconv.Itoa(int(sType))[0])
+			cells = append(cells, rabbitmq.Point{X: j, Y: y})
 		}
 	case left:
 		for j := x; j > x-(int(sType)+1); j-- {
 			b.mySea[y][j] = SeaCell(strconv.Itoa(int(sType))[0])
+			cells = append(cells, rabbitmq.Point{X: j, Y: y})
 		}
 	}
+	b.fleet = append(b.fleet, cells)
 	b.ships[sType]++
 	return nil
 }
@@ -228,52 +252,22 @@ func (b *BattleShip) CanAttack(x, y int) bool {
 	return true
 }
 
-func (b *BattleShip) hit(x, y int) (hit, destroy bool) {
-	if b.mySea[y][x] < '0' && b.mySea[y][x] > '9' { // != shipCell
-		hit, destroy = false, false
-		return
-	}
-	cntHitCell := 1 + b.cntHitCells(x+1, y, right) + b.cntHitCells(x-1, y, left) + b.cntHitCells(x, y+1, down) + b.cntHitCells(x, y-1, up)
-	if cntHitCell == int(b.mySea[x][y]) { //the entire cell of the ship was hit
-		hit, destroy = true, true
-		return
-	}
-	hit, destroy = true, false
-	return
-}
-
-// the direction to eliminate the cycle
-func (b *BattleShip) cntHitCells(x, y int, direction ShipDirection) int {
-	if x < 0 || x >= seaSize || y < 0 || y >= seaSize || b.mySea[y][x] != hitCell {
-		return 0
-	}
-	switch direction {
-	case up:
-		return 1 + b.cntHitCells(x, y-1, up)
-	case down:
-		return 1 + b.cntHitCells(x, y+1, down)
-	case right:
-		return 1 + b.cntHitCells(x+1, y, right)
-	case left:
-		return 1 + b.cntHitCells(x-1, y, left)
-	}
-	return -1
-}
-
 func (b *BattleShip) markHitOrMiss(x, y int, hit, destroy bool, mySea bool) {
-	var sea *[seaSize][seaSize]SeaCell
 	if mySea {
-		sea = &b.mySea
+		markShot(&b.mySea, x, y, hit, destroy)
 	} else {
-		sea = &b.opponentSea
+		markShot(&b.opponentSea, x, y, hit, destroy)
 	}
+}
+
+// markShot marks the shot at the sea, the cells around a destroyed ship are marked as missed
+func markShot(sea *[seaSize][seaSize]SeaCell, x, y int, hit, destroy bool) {
 	if hit {
-		b.mySea[y][x] = hitCell
+		sea[y][x] = hitCell
+	} else {
+		sea[y][x] = missCell
 	}
 	if destroy {
-		if mySea {
-			b.ships[int(b.mySea[y][x])]--
-		}
 		markDestroy(x+1, y, sea, right)
 		markDestroy(x-1, y, sea, left)
 		markDestroy(x, y-1, sea, up)
@@ -304,15 +298,6 @@ func markDestroy(x, y int, sea *[seaSize][seaSize]SeaCell, direction ShipDirecti
 	}
 }
 
-func (b *BattleShip) AllShipsDestroyed() bool {
-	for _, cnt := range b.ships {
-		if cnt != 0 {
-			return false
-		}
-	}
-	return true
-}
-
 func (b *BattleShip) AllShipsPlaced() bool {
 	for i := range b.ships {
 		if b.ships[i] != maxShips[i] {
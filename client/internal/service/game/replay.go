@@ -0,0 +1,102 @@
+package gameSrvs
+
+import (
+	"battlship/internal/service/game/domain"
+	"fmt"
+)
+
+// Replay steps through a finished game. Both fleets are shown, it is a review of the game.
+type Replay struct {
+	Match   domain.Match
+	Players [2]string
+	events  []domain.ReplayEvent
+	step    int // number of the events applied
+	seas    [2][seaSize][seaSize]SeaCell
+}
+
+func (b *BattleShip) GetReplay(matchID int64) (*Replay, error) {
+	r, err := b.mq.GetReplay(matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	replay := &Replay{
+		Match:   r.Match,
+		Players: [2]string{r.Match.Winner, r.Match.Loser},
+		events:  r.Events,
+	}
+	replay.Seek(0)
+	return replay, nil
+}
+
+// Len returns the number of the events of the game
+func (r *Replay) Len() int {
+	return len(r.events)
+}
+
+// Step returns the number of the events shown
+func (r *Replay) Step() int {
+	return r.step
+}
+
+// Seek shows the game after the first n events, the seas are rebuilt from the start
+func (r *Replay) Seek(n int) {
+	r.step = min(max(n, 0), len(r.events))
+	for i := range r.seas {
+		for y := 0; y < seaSize; y++ {
+			for x := 0; x < seaSize; x++ {
+				r.seas[i][y][x] = emptyCell
+			}
+		}
+	}
+	for _, e := range r.events[:r.step] {
+		switch e.Type {
+		case domain.ReplayFleet:
+			sea := &r.seas[r.index(e.Player)]
+			for _, ship := range e.Fleet {
+				for _, c := range ship {
+					sea[c.Y][c.X] = SeaCell('0' + rune(len(ship)-1))
+				}
+			}
+		case domain.ReplayShot:
+			target := 1 - r.index(e.Player)
+			markShot(&r.seas[target], e.X, e.Y, e.Hit, e.Destroy)
+		}
+	}
+}
+
+func (r *Replay) index(player string) int {
+	if player == r.Players[1] {
+		return 1
+	}
+	return 0
+}
+
+// Describe tells what the last shown event was
+func (r *Replay) Describe() string {
+	if r.step == 0 {
+		return fmt.Sprintf("%s vs %s, the game has not started yet", r.Players[0], r.Players[1])
+	}
+	e := r.events[r.step-1]
+	switch e.Type {
+	case domain.ReplayFleet:
+		return fmt.Sprintf("%s placed the fleet", e.Player)
+	case domain.ReplayShot:
+		coordinate := fmt.Sprintf("%c%d", 'A'+e.Y, e.X)
+		switch {
+		case e.Destroy:
+			return fmt.Sprintf("%s shoots %s and destroys the ship", e.Player, coordinate)
+		case e.Hit:
+			return fmt.Sprintf("%s shoots %s and hits", e.Player, coordinate)
+		}
+		return fmt.Sprintf("%s shoots %s and misses", e.Player, coordinate)
+	case domain.ReplayEnd:
+		return fmt.Sprintf("%s wins (%s)", e.Player, e.Reason)
+	}
+	return e.Type
+}
+
+// StringMap returns the sea of the player with the index 0 (the winner) or 1 (the loser)
+func (r *Replay) StringMap(player int) [seaSize]string {
+	return seaStrings(&r.seas[player])
+}
@@ -0,0 +1,73 @@
+package gameSrvs
+
+import "battlship/internal/adapters/rabbitmq"
+
+type resumeMQ interface {
+	GetGameState() (rabbitmq.GameState, error)
+	ForfeitGame() error
+}
+
+// UnfinishedGame returns the opponent of the game the player left running, empty if there is no such game
+func (b *BattleShip) UnfinishedGame() (opponent string, err error) {
+	state, err := b.mq.GetGameState()
+	if err != nil {
+		return "", err
+	}
+	b.resumeState = &state
+	return state.Opponent, nil
+}
+
+// Resume restores both seas of the unfinished game and continues the battle
+func (b *BattleShip) Resume() error {
+	state := b.resumeState
+	b.resumeState = nil
+	if state == nil || state.Opponent == "" {
+		return InternalError
+	}
+
+	// the messages of the resumed battle may have come already
+	err := b.startBattle()
+	if err != nil {
+		return err
+	}
+
+	for _, cells := range state.Fleet {
+		b.restoreShip(cells)
+	}
+	b.restoreShots(state.OpponentShots, true)
+	b.restoreShots(state.MyShots, false)
+
+	b.fleetSent = state.FleetPlaced
+	b.started = state.Started
+	b.myTurn = state.MyTurn
+	return nil
+}
+
+// GiveUp forfeits the unfinished game
+func (b *BattleShip) GiveUp() error {
+	b.resumeState = nil
+	return b.mq.ForfeitGame()
+}
+
+// restoreShip puts the ship placed before the reconnection back to the sea of the player
+func (b *BattleShip) restoreShip(cells []rabbitmq.Point) {
+	sType := ShipType(len(cells) - 1)
+	for _, p := range cells {
+		b.mySea[p.Y][p.X] = SeaCell('0' + rune(sType))
+	}
+	b.fleet = append(b.fleet, cells)
+	b.ships[sType]++
+}
+
+// restoreShots marks the shots made before the reconnection.
+// All hits are marked before the sunk ships, since marking a sunk ship walks along its hit cells.
+func (b *BattleShip) restoreShots(shots []rabbitmq.Shot, mySea bool) {
+	for _, s := range shots {
+		b.markHitOrMiss(s.X, s.Y, s.Hit, false, mySea)
+	}
+	for _, s := range shots {
+		if s.Destroy {
+			b.markHitOrMiss(s.X, s.Y, s.Hit, true, mySea)
+		}
+	}
+}
@@ -8,11 +8,15 @@ import (
 type serverMQ interface {
 	CreateGame(ctx context.Context) (user2 string, err error)
 	DelGame() error
+	QuickMatch(ctx context.Context) (user2 string, err error)
 	JoinGame(creatorUserName string) error
 	GetAvailableGames() (games []string, err error)
 	SaveGameResult(winner, loser string) error
 	GetUserStat(username string) (domain.Statistics, error)
 	GetOpponentName() (string, error)
+	GetHistory(page, pageSize int) (matches []domain.Match, total int, err error)
+	GetLeaderboard(page, pageSize int) (entries []domain.LeaderboardEntry, total int, myRank int, err error)
+	GetReplay(matchID int64) (domain.Replay, error)
 }
 
 func (b *BattleShip) CreateGame(ctx context.Context) (user2 string, err error) {
@@ -23,6 +27,10 @@ func (b *BattleShip) DelGame() error {
 	return b.mq.DelGame()
 }
 
+func (b *BattleShip) QuickMatch(ctx context.Context) (user2 string, err error) {
+	return b.mq.QuickMatch(ctx)
+}
+
 func (b *BattleShip) JoinGame(creatorUserName string) error {
 	return b.mq.JoinGame(creatorUserName)
 }
@@ -42,3 +50,11 @@ func (b *BattleShip) GetUserStat(username string) (domain.Statistics, error) {
 func (b *BattleShip) GetOpponentName() (string, error) {
 	return b.mq.GetOpponentName()
 }
+
+func (b *BattleShip) GetHistory(page, pageSize int) (matches []domain.Match, total int, err error) {
+	return b.mq.GetHistory(page, pageSize)
+}
+
+func (b *BattleShip) GetLeaderboard(page, pageSize int) (entries []domain.LeaderboardEntry, total int, myRank int, err error) {
+	return b.mq.GetLeaderboard(page, pageSize)
+}
@@ -0,0 +1,130 @@
+package gameSrvs
+
+import (
+	"battlship/internal/adapters/rabbitmq"
+	"battlship/internal/service/game/domain"
+	"fmt"
+)
+
+type spectateMQ interface {
+	ListLiveGames() ([]domain.LiveGame, error)
+	Watch(gameID string) (state rabbitmq.WatchState, msgs <-chan rabbitmq.SpectatorMessage, stop func(), err error)
+}
+
+// WatchedGame is a running game of other players seen by a spectator. Ships are hidden until the end.
+type WatchedGame struct {
+	Players [2]string
+	seas    [2][seaSize][seaSize]SeaCell
+	msgs    <-chan rabbitmq.SpectatorMessage
+	stop    func()
+}
+
+func (b *BattleShip) ListLiveGames() ([]domain.LiveGame, error) {
+	return b.mq.ListLiveGames()
+}
+
+// Watch starts watching the game, Close must be called when the spectator leaves
+func (b *BattleShip) Watch(gameID string) (*WatchedGame, error) {
+	state, msgs, stop, err := b.mq.Watch(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &WatchedGame{
+		Players: [2]string{state.Game.User1, state.Game.User2},
+		msgs:    msgs,
+		stop:    stop,
+	}
+	for i := range w.seas {
+		for y := 0; y < seaSize; y++ {
+			for x := 0; x < seaSize; x++ {
+				w.seas[i][y][x] = unknownCell
+			}
+		}
+	}
+	for i, player := range w.Players {
+		// all hits first, since marking a sunk ship walks along its hit cells
+		for _, s := range state.Shots[player] {
+			markShot(&w.seas[i], s.X, s.Y, s.Hit, false)
+		}
+		for _, s := range state.Shots[player] {
+			if s.Destroy {
+				markShot(&w.seas[i], s.X, s.Y, s.Hit, true)
+			}
+		}
+	}
+	return w, nil
+}
+
+// Next waits for the next event of the game and tells what happened
+func (w *WatchedGame) Next() (msgToUser string, over bool) {
+	msg, ok := <-w.msgs
+	if !ok {
+		return "Connection to the game is lost", true
+	}
+
+	switch msg.Type {
+	case rabbitmq.Ready:
+		return fmt.Sprintf("The battle began, %s shoots first", msg.Attacker), false
+	case rabbitmq.Result:
+		w.mark(msg)
+		return shotMessage(msg), false
+	case rabbitmq.End:
+		if msg.Reason == rabbitmq.AllSunk {
+			w.mark(msg)
+		}
+		w.reveal(msg.Fleets)
+		switch {
+		case msg.Winner == "":
+			return "The game is over, nobody won", true
+		case msg.Reason == rabbitmq.AllSunk:
+			return fmt.Sprintf("%s sank the last ship and wins!", msg.Winner), true
+		default:
+			return fmt.Sprintf("%s wins (%s)", msg.Winner, msg.Reason), true
+		}
+	}
+	return "", false
+}
+
+func shotMessage(msg rabbitmq.SpectatorMessage) string {
+	coordinate := fmt.Sprintf("%c%d", 'A'+msg.Y, msg.X)
+	switch {
+	case msg.Destroy:
+		return fmt.Sprintf("%s shoots %s and destroys the ship", msg.Attacker, coordinate)
+	case msg.Hit:
+		return fmt.Sprintf("%s shoots %s and hits", msg.Attacker, coordinate)
+	}
+	return fmt.Sprintf("%s shoots %s and misses", msg.Attacker, coordinate)
+}
+
+// mark marks the shot at the sea of the player who did not shoot
+func (w *WatchedGame) mark(msg rabbitmq.SpectatorMessage) {
+	target := 0
+	if msg.Attacker == w.Players[0] {
+		target = 1
+	}
+	markShot(&w.seas[target], msg.X, msg.Y, msg.Hit, msg.Destroy)
+}
+
+// reveal shows the ships that were not hit
+func (w *WatchedGame) reveal(fleets map[string][][]rabbitmq.Point) {
+	for i, player := range w.Players {
+		for _, ship := range fleets[player] {
+			for _, p := range ship {
+				if w.seas[i][p.Y][p.X] != hitCell {
+					w.seas[i][p.Y][p.X] = shipCell
+				}
+			}
+		}
+	}
+}
+
+// StringMap returns the sea of the player with the index 0 or 1
+func (w *WatchedGame) StringMap(player int) [seaSize]string {
+	return seaStrings(&w.seas[player])
+}
+
+// Close stops watching the game
+func (w *WatchedGame) Close() {
+	w.stop()
+}
@@ -1,6 +1,7 @@
 package authUI
 
 import (
+	"battlship/internal/ui/terminal/input"
 	"fmt"
 	"os"
 )
@@ -9,7 +10,7 @@ type authService interface {
 	Login(login, password string) error
 	Register(login, password string) error
 	GerUserLogin() string
-	Logout()
+	Logout() error
 }
 
 type AuthUI struct {
@@ -29,7 +30,7 @@ func (a *AuthUI) Authorization() {
 		var password string
 		for { // while invalid input
 			fmt.Println("Login: ")
-			cntScan, err := fmt.Scan(&login)
+			cntScan, err := input.Scan(&login)
 			if err != nil || cntScan != 1 {
 				fmt.Println("Invalid input")
 			} else {
@@ -38,7 +39,7 @@ func (a *AuthUI) Authorization() {
 		}
 		for { // while invalid input
 			fmt.Println("Password: ")
-			cntScan, err := fmt.Scan(&password)
+			cntScan, err := input.Scan(&password)
 			if err != nil || cntScan != 1 {
 				fmt.Println("Invalid input")
 			} else {
@@ -49,7 +50,7 @@ func (a *AuthUI) Authorization() {
 		var command int
 		for { // while invalid input
 			fmt.Println("Select command: \n1. Login\n2. Register\n3. Exit\nEnter number of command: ")
-			cntScan, err := fmt.Scan(&command)
+			cntScan, err := input.Scan(&command)
 			if cntScan == 1 && err == nil {
 				switch command {
 				case 1:
@@ -79,3 +80,12 @@ func (a *AuthUI) Authorization() {
 func (a *AuthUI) GetUserName() string {
 	return a.auth.GerUserLogin()
 }
+
+func (a *AuthUI) Logout() {
+	err := a.auth.Logout()
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	fmt.Println("Logged out")
+}
@@ -3,7 +3,9 @@ package gameUI
 import (
 	gameSrvs "battlship/internal/service/game"
 	"battlship/internal/service/game/domain"
+	"battlship/internal/ui/terminal/input"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -13,11 +15,17 @@ import (
 type gameServer interface {
 	CreateGame(ctx context.Context) (user2 string, err error)
 	DelGame() error
+	QuickMatch(ctx context.Context) (user2 string, err error)
 	JoinGame(creatorUserName string) error
 	GetAvailableGames() (games []string, err error)
 	SaveGameResult(winner, loser string) error
 	GetUserStat(username string) (domain.Statistics, error)
 	GetOpponentName() (string, error)
+	GetHistory(page, pageSize int) (matches []domain.Match, total int, err error)
+	GetLeaderboard(page, pageSize int) (entries []domain.LeaderboardEntry, total int, myRank int, err error)
+	GetReplay(matchID int64) (*gameSrvs.Replay, error)
+	ListLiveGames() ([]domain.LiveGame, error)
+	Watch(gameID string) (*gameSrvs.WatchedGame, error)
 }
 
 type gameBattle interface {
@@ -25,13 +33,17 @@ type gameBattle interface {
 	Ready() (iFirst bool, err error)
 	Attack(x, y int) (msgToUser string, err error)
 	Defend() (msgToUser string, err error)
+	SendChat(text string) error
+	ChatMessages() <-chan gameSrvs.ChatMessage
+	UnfinishedGame() (opponent string, err error)
+	Resume() error
+	GiveUp() error
 }
 
 type gameMap interface {
 	StringMap(myMap bool) [10]string
 	PlaceShip(x, y int, sType gameSrvs.ShipType, direction gameSrvs.ShipDirection) error
 	CanAttack(x, y int) bool
-	AllShipsDestroyed() bool
 	AllShipsPlaced() bool
 	GetAvailCntShipType(sType gameSrvs.ShipType) int
 }
@@ -65,19 +77,24 @@ func (g *GameUI) printMap(my bool) {
 	fmt.Println("  | 0 1 2 3 4 5 6 7 8 9 |")
 }
 
-func (g *GameUI) StartGame(userName string) {
+// StartGame shows the lobby until a battle is started or the user decides to log out
+func (g *GameUI) StartGame(userName string) (logout bool) {
 	userStats, err := g.game.GetUserStat(userName)
 	if err != nil {
 		fmt.Println(err)
-		return
+		return false
 	}
 	fmt.Println("Your statistics: ", userStats)
 
+	if g.offerResume() {
+		return false
+	}
+
 	battleStarted := false
 	for !battleStarted {
-		fmt.Println("Select command: \n1. Create game\n2. Get available games\n3. Exit\nEnter number of command: ")
+		fmt.Println("Select command: \n1. Create game\n2. Get available games\n3. Quick match\n4. Watch game\n5. Match history\n6. Leaderboard\n7. Log out / switch account\n8. Exit\nEnter number of command: ")
 		var command int
-		cntScan, err := fmt.Scan(&command)
+		cntScan, err := input.Scan(&command)
 		if err != nil || cntScan != 1 {
 			fmt.Println("Invalid input")
 		} else {
@@ -134,7 +151,7 @@ func (g *GameUI) StartGame(userName string) {
 				for { // while invalid input
 					fmt.Println("Enter number of game: ")
 					var gameNumber int
-					cntScan, err := fmt.Scan(&gameNumber)
+					cntScan, err := input.Scan(&gameNumber)
 					if err != nil || cntScan != 1 || gameNumber < 1 || gameNumber > len(games) {
 						fmt.Println("Invalid input")
 					} else {
@@ -153,13 +170,248 @@ func (g *GameUI) StartGame(userName string) {
 					}
 				}
 			case 3:
+				fmt.Println("Looking for an opponent of a similar rating...")
+				user2Name, err := g.game.QuickMatch(context.Background())
+				if err != nil {
+					fmt.Println(err)
+					continue
+				}
+				fmt.Println("Opponent found: ", user2Name)
+				err = g.game.StartBattle()
+				if err != nil {
+					fmt.Println(err)
+				} else {
+					battleStarted = true
+				}
+			case 4:
+				g.watchGame()
+			case 5:
+				g.showHistory(userName)
+			case 6:
+				g.showLeaderboard()
+			case 7:
+				return true
+			case 8:
 				os.Exit(0)
 			}
 		}
 	}
+	return false
+}
+
+// offerResume lets the user continue the game left running by a crash or a lost connection
+func (g *GameUI) offerResume() (resumed bool) {
+	opponent, err := g.game.UnfinishedGame()
+	if err != nil {
+		fmt.Println(err)
+		return false
+	}
+	if opponent == "" {
+		return false
+	}
+
+	for {
+		fmt.Printf("You have an unfinished game against %s\n", opponent)
+		fmt.Println("Select command: \n1. Resume game\n2. Give up\nEnter number of command: ")
+		var command int
+		cntScan, err := input.Scan(&command)
+		if err != nil || cntScan != 1 {
+			fmt.Println("Invalid input")
+			continue
+		}
+		switch command {
+		case 1:
+			err = g.game.Resume()
+			if err != nil {
+				fmt.Println(err)
+				return false
+			}
+			return true
+		case 2:
+			err = g.game.GiveUp()
+			if err != nil {
+				fmt.Println(err)
+			}
+			return false
+		default:
+			fmt.Println("Invalid number of command")
+		}
+	}
+}
+
+// watchGame lets the user pick a running game and shows its shots until it ends
+func (g *GameUI) watchGame() {
+	games, err := g.game.ListLiveGames()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if len(games) == 0 {
+		fmt.Println("No games are being played now")
+		return
+	}
+	fmt.Println("Games being played: ")
+	for i, game := range games {
+		fmt.Println(i+1, ". ", game)
+	}
+
+	var gameNumber int
+	for {
+		fmt.Println("Enter number of game: ")
+		cntScan, err := input.Scan(&gameNumber)
+		if err != nil || cntScan != 1 || gameNumber < 1 || gameNumber > len(games) {
+			fmt.Println("Invalid input")
+			continue
+		}
+		break
+	}
+
+	watched, err := g.game.Watch(games[gameNumber-1].ID)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer watched.Close()
+
+	fmt.Println("Watching the game until it ends...")
+	g.printWatched(watched)
+	for {
+		msg, over := watched.Next()
+		fmt.Println(msg)
+		g.printWatched(watched)
+		if over {
+			return
+		}
+	}
+}
+
+func (g *GameUI) printWatched(w *gameSrvs.WatchedGame) {
+	for i, player := range w.Players {
+		fmt.Printf("%s's sea: \n", player)
+		sea := w.StringMap(i)
+		for j := range sea {
+			fmt.Println(string(rune('A'+j)), "|", sea[j], "|")
+		}
+		fmt.Println("  | 0 1 2 3 4 5 6 7 8 9 |")
+	}
+}
+
+const historyPageSize = 10
+
+// showHistory lists the finished games of the user page by page
+func (g *GameUI) showHistory(userName string) {
+	page := 1
+	for {
+		matches, total, err := g.game.GetHistory(page, historyPageSize)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if total == 0 {
+			fmt.Println("No finished games yet")
+			return
+		}
+		pages := (total + historyPageSize - 1) / historyPageSize
+		fmt.Printf("Match history, page %d of %d:\n", page, pages)
+		for i, m := range matches {
+			delta := m.WinnerRatingDelta
+			if m.Loser == userName {
+				delta = m.LoserRatingDelta
+			}
+			fmt.Printf("%d. %v  rating %+.0f\n", i+1, m, delta)
+		}
+
+		fmt.Println("Select command: \n1. Next page\n2. Previous page\n3. Replay a game\n4. Back\nEnter number of command: ")
+		var command int
+		cntScan, err := input.Scan(&command)
+		if err != nil || cntScan != 1 {
+			fmt.Println("Invalid input")
+			continue
+		}
+		switch command {
+		case 1:
+			if page < pages {
+				page++
+			}
+		case 2:
+			if page > 1 {
+				page--
+			}
+		case 3:
+			fmt.Println("Enter number of game: ")
+			var gameNumber int
+			cntScan, err := input.Scan(&gameNumber)
+			if err != nil || cntScan != 1 || gameNumber < 1 || gameNumber > len(matches) {
+				fmt.Println("Invalid input")
+				continue
+			}
+			g.showReplay(matches[gameNumber-1].ID)
+		case 4:
+			return
+		default:
+			fmt.Println("Invalid number of command")
+		}
+	}
+}
+
+const leaderboardPageSize = 20
+
+// showLeaderboard lists the ranked players page by page
+func (g *GameUI) showLeaderboard() {
+	page := 1
+	for {
+		entries, total, myRank, err := g.game.GetLeaderboard(page, leaderboardPageSize)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if total == 0 {
+			fmt.Println("Nobody has played yet")
+			return
+		}
+		pages := (total + leaderboardPageSize - 1) / leaderboardPageSize
+		fmt.Printf("Leaderboard, page %d of %d:\n", page, pages)
+		for _, e := range entries {
+			fmt.Println(e)
+		}
+		if myRank > 0 {
+			fmt.Printf("Your rank: %d of %d\n", myRank, total)
+		} else {
+			fmt.Println("You are not ranked yet, play a game first")
+		}
+
+		fmt.Println("Select command: \n1. Next page\n2. Previous page\n3. Page with me\n4. Back\nEnter number of command: ")
+		var command int
+		cntScan, err := input.Scan(&command)
+		if err != nil || cntScan != 1 {
+			fmt.Println("Invalid input")
+			continue
+		}
+		switch command {
+		case 1:
+			if page < pages {
+				page++
+			}
+		case 2:
+			if page > 1 {
+				page--
+			}
+		case 3:
+			if myRank > 0 {
+				page = (myRank-1)/leaderboardPageSize + 1
+			}
+		case 4:
+			return
+		default:
+			fmt.Println("Invalid number of command")
+		}
+	}
 }
 
 func (g *GameUI) ReadyToBattle() {
+	if g.game.AllShipsPlaced() { // the fleet of a resumed game
+		return
+	}
 	fmt.Println("It's time to place the ships")
 	curShipType := gameSrvs.FourDeck
 	for !g.game.AllShipsPlaced() {
@@ -178,30 +430,14 @@ func (g *GameUI) ReadyToBattle() {
 			case gameSrvs.SingleDeck:
 				fmt.Println("Place one-deck ship")
 			}
-			var x, y int
-			fmt.Println("Select coordinate: ")
-			var coordinate string
-			cntScan, err := fmt.Scan(&coordinate)
-			if err != nil || cntScan != 1 {
-				fmt.Println("Invalid input")
-				continue
-			}
-
-			coordinate = strings.ToUpper(coordinate)
-			if len(coordinate) != 2 {
-				fmt.Println("Invalid coordinate")
-				continue
-			}
-			y = int(coordinate[0] - 'A')
-			x, err = strconv.Atoi(string(coordinate[1]))
-			if err != nil || x < 0 || x > 9 || y < 0 || y > 9 {
-				fmt.Println("Invalid coordinate")
+			x, y, ok := scanCoordinate()
+			if !ok {
 				continue
 			}
 
 			fmt.Println("Select direction: \n1. Up\n2. Down\n3. Left\n4. Right\nEnter number of direction: ")
 			var direction int
-			cntScan, err = fmt.Scan(&direction)
+			cntScan, err := input.Scan(&direction)
 			if err != nil || cntScan != 1 {
 				fmt.Println("Invalid input")
 				continue
@@ -216,96 +452,138 @@ func (g *GameUI) ReadyToBattle() {
 	}
 }
 
+// scanCoordinate reads a coordinate like "B7": the letter is the row, the digit is the column
+func scanCoordinate() (x, y int, ok bool) {
+	fmt.Println("Select coordinate: ")
+	var coordinate string
+	cntScan, err := input.Scan(&coordinate)
+	if err != nil || cntScan != 1 {
+		fmt.Println("Invalid input")
+		return 0, 0, false
+	}
+
+	coordinate = strings.ToUpper(coordinate)
+	if len(coordinate) != 2 {
+		fmt.Println("Invalid coordinate")
+		return 0, 0, false
+	}
+	y = int(coordinate[0] - 'A')
+	x, err = strconv.Atoi(string(coordinate[1]))
+	if err != nil || x < 0 || x > 9 || y < 0 || y > 9 {
+		fmt.Println("Invalid coordinate")
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
 func (g *GameUI) StartBattle() (win bool) {
-	fmt.Println("Ready!")
-	IFirst, err := g.game.Ready()
-	if err != nil {
+	stopChat := g.startChat()
+	defer stopChat()
+
+	fmt.Println("Ready! Waiting for the opponent's fleet...")
+	myTurn, err := g.game.Ready()
+	var over *gameSrvs.GameOverError
+	if errors.As(err, &over) {
+		fmt.Println(over.MsgToUser)
+		return gameSrvs.Won(over.MsgToUser)
+	} else if err != nil {
 		fmt.Println(err)
+		return false
 	}
 	fmt.Println("Battle started")
-	if IFirst {
-		for !g.game.AllShipsDestroyed() {
+	for {
+		if myTurn {
 			g.printMap(false)
-			//Attack
-			var x, y int
-			for {
-				fmt.Println("Select coordinate: ")
-				cntScan, err := fmt.Scan(&x, &y)
-				if err != nil || cntScan != 2 {
-					fmt.Println("Invalid input")
-					continue
-				}
-				if x < 0 || x > 9 || y < 0 || y > 9 {
-					fmt.Println("Invalid coordinate")
-					continue
-				}
-				if !g.game.CanAttack(y, x) {
-					fmt.Println("Already attacked")
-					continue
-				}
-				break
-			}
-			msg, err := g.game.Attack(y, x)
+			msg, err := g.attack()
 			if err != nil {
 				fmt.Println(err)
+				return false
 			}
 			fmt.Println(msg)
-			if msg == gameSrvs.Win {
-				break
+			if gameSrvs.Won(msg) {
+				return true
+			}
+			if gameSrvs.Lost(msg) {
+				return false
 			}
 			g.printMap(false)
-
-			//Defend
-			msg, err = g.game.Defend()
+		} else {
+			fmt.Println("Waiting for the enemy's shot...")
+			msg, err := g.game.Defend()
 			if err != nil {
 				fmt.Println(err)
+				return false
 			}
 			fmt.Println(msg)
-			if msg == gameSrvs.Lose {
-				break
+			if gameSrvs.Lost(msg) {
+				return false
+			}
+			if gameSrvs.Won(msg) {
+				return true
 			}
 			g.printMap(true)
 		}
-	} else {
-		for !g.game.AllShipsDestroyed() {
-			//Defend
-			msg, err := g.game.Defend()
-			if err != nil {
-				fmt.Println(err)
-			}
-			fmt.Println(msg)
-			if msg == gameSrvs.Lose {
-				win = false
-				break
-			}
+		myTurn = !myTurn
+	}
+}
 
-			//Attack
-			var x, y int
-			for {
-				fmt.Println("Select coordinate: ")
-				cntScan, err := fmt.Scan(&x, &y)
-				if err != nil || cntScan != 2 {
-					fmt.Println("Invalid input")
-					continue
+// startChat shows the chat messages of the opponent and sends the lines starting with input.ChatPrefix
+// until the returned function is called
+func (g *GameUI) startChat() (stop func()) {
+	fmt.Printf("Start a line with %s to send a chat message to the opponent\n", input.ChatPrefix)
+	input.SetChat(func(text string) {
+		err := g.game.SendChat(text)
+		if err != nil {
+			fmt.Println(err)
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		msgs := g.game.ChatMessages()
+		for {
+			select {
+			case <-done:
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
 				}
-				if x < 0 || x > 9 || y < 0 || y > 9 {
-					fmt.Println("Invalid coordinate")
+				if msg.Err != "" {
+					fmt.Println("[chat] message not sent:", msg.Err)
 					continue
 				}
-				break
-			}
-			msg, err = g.game.Attack(x, y)
-			if err != nil {
-				fmt.Println(err)
-			}
-			fmt.Println(msg)
-			if msg == gameSrvs.Win {
-				win = true
-				break
+				fmt.Printf("[chat] %s: %s\n", msg.From, msg.Text)
 			}
 		}
+	}()
+
+	return func() {
+		input.SetChat(nil)
+		close(done)
+	}
+}
+
+// attack asks for a coordinate until the server accepts the shot
+func (g *GameUI) attack() (msgToUser string, err error) {
+	for {
+		x, y, ok := scanCoordinate()
+		if !ok {
+			continue
+		}
+		if !g.game.CanAttack(x, y) {
+			fmt.Println("Already attacked")
+			continue
+		}
+		msgToUser, err = g.game.Attack(x, y)
+		if errors.Is(err, gameSrvs.InternalError) {
+			return "", err
+		} else if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		return msgToUser, nil
 	}
-	return win
 }
 
 func (g *GameUI) GetOpponentName() string {
@@ -0,0 +1,58 @@
+package gameUI
+
+import (
+	gameSrvs "battlship/internal/service/game"
+	"battlship/internal/ui/terminal/input"
+	"fmt"
+)
+
+// showReplay steps through the finished game forward and back
+func (g *GameUI) showReplay(matchID int64) {
+	replay, err := g.game.GetReplay(matchID)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if replay.Len() == 0 {
+		fmt.Println("The game was not recorded")
+		return
+	}
+
+	for {
+		fmt.Printf("Move %d of %d: %s\n", replay.Step(), replay.Len(), replay.Describe())
+		g.printReplay(replay)
+
+		fmt.Println("Select command: \n1. Forward\n2. Back\n3. To the start\n4. To the end\n5. Leave replay\nEnter number of command: ")
+		var command int
+		cntScan, err := input.Scan(&command)
+		if err != nil || cntScan != 1 {
+			fmt.Println("Invalid input")
+			continue
+		}
+		switch command {
+		case 1:
+			replay.Seek(replay.Step() + 1)
+		case 2:
+			replay.Seek(replay.Step() - 1)
+		case 3:
+			replay.Seek(0)
+		case 4:
+			replay.Seek(replay.Len())
+		case 5:
+			return
+		default:
+			fmt.Println("Invalid number of command")
+		}
+	}
+}
+
+func (g *GameUI) printReplay(r *gameSrvs.Replay) {
+	for i, player := range r.Players {
+		fmt.Printf("%s's sea: \n", player)
+		sea := r.StringMap(i)
+		for j := range sea {
+			fmt.Println(string(rune('A'+j)), "|", sea[j], "|")
+		}
+		fmt.Println("  | 0 1 2 3 4 5 6 7 8 9 |")
+	}
+}
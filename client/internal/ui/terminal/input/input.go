@@ -0,0 +1,75 @@
+// Package input is the only reader of the standard input of the terminal UI.
+// It reads line by line in the background, so the user can chat at any moment of the battle
+// without breaking the entry of coordinates and commands.
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ChatPrefix starts a line that is sent to the opponent instead of being read as input
+const ChatPrefix = "!"
+
+type reader struct {
+	lines chan string
+
+	mu   sync.Mutex
+	chat func(text string)
+}
+
+var std = newReader(os.Stdin)
+
+func newReader(r io.Reader) *reader {
+	rd := &reader{lines: make(chan string)}
+	go rd.run(r)
+	return rd
+}
+
+func (rd *reader) run(r io.Reader) {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+
+		rd.mu.Lock()
+		chat := rd.chat
+		rd.mu.Unlock()
+		if text, ok := strings.CutPrefix(line, ChatPrefix); ok && chat != nil {
+			chat(strings.TrimSpace(text))
+			continue
+		}
+
+		if chat == nil {
+			rd.lines <- line
+			continue
+		}
+		select {
+		case rd.lines <- line:
+		default: // nobody asked for input during the battle, e.g. while the opponent is shooting
+			fmt.Printf("Wait for your turn. Start the line with %s to chat\n", ChatPrefix)
+		}
+	}
+	close(rd.lines)
+}
+
+// Scan waits for the next non-empty line and scans it like fmt.Sscan
+func Scan(a ...any) (n int, err error) {
+	for line := range std.lines {
+		if line == "" {
+			continue
+		}
+		return fmt.Sscan(line, a...)
+	}
+	return 0, io.EOF
+}
+
+// SetChat makes the lines starting with ChatPrefix go to the handler, nil turns the chat off
+func SetChat(handler func(text string)) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	std.chat = handler
+}
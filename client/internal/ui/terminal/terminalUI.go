@@ -1,38 +1,64 @@
 package terminalUI
 
+import "fmt"
+
 type auth interface {
 	Authorization()
 	GetUserName() string
+	Logout()
 }
 
 type game interface {
-	StartGame(userName string)
+	StartGame(userName string) (logout bool)
 	ReadyToBattle()
 	StartBattle() (win bool)
 	SendResult(user1, user2 string)
 	GetOpponentName() string
 }
 
+type connection interface {
+	ConnectionStates() <-chan bool // false when the connection to the server is lost, true when it is restored
+}
+
 type TerminalUI struct {
 	auth auth
 	game game
+	conn connection
 }
 
-func New(a auth, g game) *TerminalUI {
+func New(a auth, g game, c connection) *TerminalUI {
 	return &TerminalUI{
 		auth: a,
 		game: g,
+		conn: c,
 	}
 }
 
 func (f *TerminalUI) MustRun() {
-	f.auth.Authorization()
+	go f.watchConnection()
 	for {
-		f.game.StartGame(f.auth.GetUserName())
-		f.game.ReadyToBattle()
-		win := f.game.StartBattle()
-		if win {
-			f.game.SendResult(f.auth.GetUserName(), f.game.GetOpponentName())
+		f.auth.Authorization()
+		for {
+			if logout := f.game.StartGame(f.auth.GetUserName()); logout {
+				f.auth.Logout()
+				break
+			}
+			f.game.ReadyToBattle()
+			win := f.game.StartBattle()
+			if win {
+				f.game.SendResult(f.auth.GetUserName(), f.game.GetOpponentName())
+			}
+		}
+	}
+}
+
+// watchConnection tells the player when the connection to the server is lost and restored
+func (f *TerminalUI) watchConnection() {
+	for connected := range f.conn.ConnectionStates() {
+		if connected {
+			fmt.Println("\nReconnected to the server")
+		} else {
+			fmt.Println("\nConnection to the server lost, reconnecting...")
 		}
 	}
 }
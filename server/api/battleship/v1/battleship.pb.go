@@ -0,0 +1,2790 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: battleship/v1/battleship.proto
+
+// The API for the tools and the bots. The breaking changes go to a new version of the package.
+
+package battleshipv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RegisterRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+}
+
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequest) ProtoMessage() {}
+
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RegisterRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type RegisterResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *RegisterResponse) Reset() {
+	*x = RegisterResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterResponse) ProtoMessage() {}
+
+func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
+func (*RegisterResponse) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RegisterResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type LoginRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+}
+
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequest) ProtoMessage() {}
+
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LoginRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type LoginResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *LoginResponse) Reset() {
+	*x = LoginResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginResponse) ProtoMessage() {}
+
+func (x *LoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
+func (*LoginResponse) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LoginResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type LogoutRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *LogoutRequest) Reset() {
+	*x = LogoutRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutRequest) ProtoMessage() {}
+
+func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{4}
+}
+
+type LogoutResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *LogoutResponse) Reset() {
+	*x = LogoutResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogoutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutResponse) ProtoMessage() {}
+
+func (x *LogoutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutResponse.ProtoReflect.Descriptor instead.
+func (*LogoutResponse) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{5}
+}
+
+type CreateGameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CreateGameRequest) Reset() {
+	*x = CreateGameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGameRequest) ProtoMessage() {}
+
+func (x *CreateGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGameRequest.ProtoReflect.Descriptor instead.
+func (*CreateGameRequest) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{6}
+}
+
+type CreateGameResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Opponent string `protobuf:"bytes,1,opt,name=opponent,proto3" json:"opponent,omitempty"`
+}
+
+func (x *CreateGameResponse) Reset() {
+	*x = CreateGameResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateGameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGameResponse) ProtoMessage() {}
+
+func (x *CreateGameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGameResponse.ProtoReflect.Descriptor instead.
+func (*CreateGameResponse) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateGameResponse) GetOpponent() string {
+	if x != nil {
+		return x.Opponent
+	}
+	return ""
+}
+
+type JoinGameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CreatorUserName string `protobuf:"bytes,1,opt,name=creator_user_name,json=creatorUserName,proto3" json:"creator_user_name,omitempty"`
+}
+
+func (x *JoinGameRequest) Reset() {
+	*x = JoinGameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JoinGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinGameRequest) ProtoMessage() {}
+
+func (x *JoinGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinGameRequest.ProtoReflect.Descriptor instead.
+func (*JoinGameRequest) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *JoinGameRequest) GetCreatorUserName() string {
+	if x != nil {
+		return x.CreatorUserName
+	}
+	return ""
+}
+
+type JoinGameResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *JoinGameResponse) Reset() {
+	*x = JoinGameResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JoinGameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinGameResponse) ProtoMessage() {}
+
+func (x *JoinGameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinGameResponse.ProtoReflect.Descriptor instead.
+func (*JoinGameResponse) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{9}
+}
+
+type ListGamesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListGamesRequest) Reset() {
+	*x = ListGamesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListGamesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGamesRequest) ProtoMessage() {}
+
+func (x *ListGamesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGamesRequest.ProtoReflect.Descriptor instead.
+func (*ListGamesRequest) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{10}
+}
+
+type ListGamesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Creators []string `protobuf:"bytes,1,rep,name=creators,proto3" json:"creators,omitempty"`
+}
+
+func (x *ListGamesResponse) Reset() {
+	*x = ListGamesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListGamesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGamesResponse) ProtoMessage() {}
+
+func (x *ListGamesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGamesResponse.ProtoReflect.Descriptor instead.
+func (*ListGamesResponse) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListGamesResponse) GetCreators() []string {
+	if x != nil {
+		return x.Creators
+	}
+	return nil
+}
+
+type DelGameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DelGameRequest) Reset() {
+	*x = DelGameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DelGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelGameRequest) ProtoMessage() {}
+
+func (x *DelGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelGameRequest.ProtoReflect.Descriptor instead.
+func (*DelGameRequest) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{12}
+}
+
+type DelGameResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DelGameResponse) Reset() {
+	*x = DelGameResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DelGameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelGameResponse) ProtoMessage() {}
+
+func (x *DelGameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelGameResponse.ProtoReflect.Descriptor instead.
+func (*DelGameResponse) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{13}
+}
+
+type GetUserStatRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserName string `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+}
+
+func (x *GetUserStatRequest) Reset() {
+	*x = GetUserStatRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUserStatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserStatRequest) ProtoMessage() {}
+
+func (x *GetUserStatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserStatRequest.ProtoReflect.Descriptor instead.
+func (*GetUserStatRequest) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetUserStatRequest) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+type GetUserStatResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rating int32 `protobuf:"varint,1,opt,name=rating,proto3" json:"rating,omitempty"`
+	Wins   int32 `protobuf:"varint,2,opt,name=wins,proto3" json:"wins,omitempty"`
+	Losses int32 `protobuf:"varint,3,opt,name=losses,proto3" json:"losses,omitempty"`
+}
+
+func (x *GetUserStatResponse) Reset() {
+	*x = GetUserStatResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUserStatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserStatResponse) ProtoMessage() {}
+
+func (x *GetUserStatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserStatResponse.ProtoReflect.Descriptor instead.
+func (*GetUserStatResponse) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetUserStatResponse) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *GetUserStatResponse) GetWins() int32 {
+	if x != nil {
+		return x.Wins
+	}
+	return 0
+}
+
+func (x *GetUserStatResponse) GetLosses() int32 {
+	if x != nil {
+		return x.Losses
+	}
+	return 0
+}
+
+type GetHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserName string `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"` // the user of the session if empty
+	Page     int32  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`                        // starting from 1
+	PageSize int32  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+}
+
+func (x *GetHistoryRequest) Reset() {
+	*x = GetHistoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryRequest) ProtoMessage() {}
+
+func (x *GetHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetHistoryRequest) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *GetHistoryRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetHistoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type Match struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id                int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Winner            string                 `protobuf:"bytes,2,opt,name=winner,proto3" json:"winner,omitempty"`
+	Loser             string                 `protobuf:"bytes,3,opt,name=loser,proto3" json:"loser,omitempty"`
+	StartedAt         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	EndedAt           *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=ended_at,json=endedAt,proto3" json:"ended_at,omitempty"`
+	Shots             int32                  `protobuf:"varint,6,opt,name=shots,proto3" json:"shots,omitempty"`
+	WinnerRatingDelta float64                `protobuf:"fixed64,7,opt,name=winner_rating_delta,json=winnerRatingDelta,proto3" json:"winner_rating_delta,omitempty"`
+	LoserRatingDelta  float64                `protobuf:"fixed64,8,opt,name=loser_rating_delta,json=loserRatingDelta,proto3" json:"loser_rating_delta,omitempty"`
+	EndReason         string                 `protobuf:"bytes,9,opt,name=end_reason,json=endReason,proto3" json:"end_reason,omitempty"`
+}
+
+func (x *Match) Reset() {
+	*x = Match{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Match) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Match) ProtoMessage() {}
+
+func (x *Match) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Match.ProtoReflect.Descriptor instead.
+func (*Match) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Match) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Match) GetWinner() string {
+	if x != nil {
+		return x.Winner
+	}
+	return ""
+}
+
+func (x *Match) GetLoser() string {
+	if x != nil {
+		return x.Loser
+	}
+	return ""
+}
+
+func (x *Match) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Match) GetEndedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndedAt
+	}
+	return nil
+}
+
+func (x *Match) GetShots() int32 {
+	if x != nil {
+		return x.Shots
+	}
+	return 0
+}
+
+func (x *Match) GetWinnerRatingDelta() float64 {
+	if x != nil {
+		return x.WinnerRatingDelta
+	}
+	return 0
+}
+
+func (x *Match) GetLoserRatingDelta() float64 {
+	if x != nil {
+		return x.LoserRatingDelta
+	}
+	return 0
+}
+
+func (x *Match) GetEndReason() string {
+	if x != nil {
+		return x.EndReason
+	}
+	return ""
+}
+
+type GetHistoryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Matches []*Match `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	Total   int32    `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+}
+
+func (x *GetHistoryResponse) Reset() {
+	*x = GetHistoryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryResponse) ProtoMessage() {}
+
+func (x *GetHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetHistoryResponse) GetMatches() []*Match {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+func (x *GetHistoryResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetLeaderboardRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Page     int32 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"` // starting from 1
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+}
+
+func (x *GetLeaderboardRequest) Reset() {
+	*x = GetLeaderboardRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLeaderboardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLeaderboardRequest) ProtoMessage() {}
+
+func (x *GetLeaderboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLeaderboardRequest.ProtoReflect.Descriptor instead.
+func (*GetLeaderboardRequest) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetLeaderboardRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetLeaderboardRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type LeaderboardEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rank     int32  `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	UserName string `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	Rating   int32  `protobuf:"varint,3,opt,name=rating,proto3" json:"rating,omitempty"`
+	Wins     int32  `protobuf:"varint,4,opt,name=wins,proto3" json:"wins,omitempty"`
+	Losses   int32  `protobuf:"varint,5,opt,name=losses,proto3" json:"losses,omitempty"`
+}
+
+func (x *LeaderboardEntry) Reset() {
+	*x = LeaderboardEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeaderboardEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaderboardEntry) ProtoMessage() {}
+
+func (x *LeaderboardEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaderboardEntry.ProtoReflect.Descriptor instead.
+func (*LeaderboardEntry) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *LeaderboardEntry) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *LeaderboardEntry) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetWins() int32 {
+	if x != nil {
+		return x.Wins
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetLosses() int32 {
+	if x != nil {
+		return x.Losses
+	}
+	return 0
+}
+
+type GetLeaderboardResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*LeaderboardEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Total   int32               `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	MyRank  int32               `protobuf:"varint,3,opt,name=my_rank,json=myRank,proto3" json:"my_rank,omitempty"` // 0 if the user has not played yet
+}
+
+func (x *GetLeaderboardResponse) Reset() {
+	*x = GetLeaderboardResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLeaderboardResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLeaderboardResponse) ProtoMessage() {}
+
+func (x *GetLeaderboardResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLeaderboardResponse.ProtoReflect.Descriptor instead.
+func (*GetLeaderboardResponse) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetLeaderboardResponse) GetEntries() []*LeaderboardEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *GetLeaderboardResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetLeaderboardResponse) GetMyRank() int32 {
+	if x != nil {
+		return x.MyRank
+	}
+	return 0
+}
+
+type Point struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X int32 `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y int32 `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Point) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Point) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+type Ship struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cells []*Point `protobuf:"bytes,1,rep,name=cells,proto3" json:"cells,omitempty"`
+}
+
+func (x *Ship) Reset() {
+	*x = Ship{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Ship) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ship) ProtoMessage() {}
+
+func (x *Ship) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ship.ProtoReflect.Descriptor instead.
+func (*Ship) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Ship) GetCells() []*Point {
+	if x != nil {
+		return x.Cells
+	}
+	return nil
+}
+
+type BattleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Move:
+	//	*BattleRequest_PlaceFleet_
+	//	*BattleRequest_Attack_
+	//	*BattleRequest_Heartbeat_
+	//	*BattleRequest_Chat_
+	Move isBattleRequest_Move `protobuf_oneof:"move"`
+}
+
+func (x *BattleRequest) Reset() {
+	*x = BattleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BattleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BattleRequest) ProtoMessage() {}
+
+func (x *BattleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BattleRequest.ProtoReflect.Descriptor instead.
+func (*BattleRequest) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{24}
+}
+
+func (m *BattleRequest) GetMove() isBattleRequest_Move {
+	if m != nil {
+		return m.Move
+	}
+	return nil
+}
+
+func (x *BattleRequest) GetPlaceFleet() *BattleRequest_PlaceFleet {
+	if x, ok := x.GetMove().(*BattleRequest_PlaceFleet_); ok {
+		return x.PlaceFleet
+	}
+	return nil
+}
+
+func (x *BattleRequest) GetAttack() *BattleRequest_Attack {
+	if x, ok := x.GetMove().(*BattleRequest_Attack_); ok {
+		return x.Attack
+	}
+	return nil
+}
+
+func (x *BattleRequest) GetHeartbeat() *BattleRequest_Heartbeat {
+	if x, ok := x.GetMove().(*BattleRequest_Heartbeat_); ok {
+		return x.Heartbeat
+	}
+	return nil
+}
+
+func (x *BattleRequest) GetChat() *BattleRequest_Chat {
+	if x, ok := x.GetMove().(*BattleRequest_Chat_); ok {
+		return x.Chat
+	}
+	return nil
+}
+
+type isBattleRequest_Move interface {
+	isBattleRequest_Move()
+}
+
+type BattleRequest_PlaceFleet_ struct {
+	PlaceFleet *BattleRequest_PlaceFleet `protobuf:"bytes,1,opt,name=place_fleet,json=placeFleet,proto3,oneof"`
+}
+
+type BattleRequest_Attack_ struct {
+	Attack *BattleRequest_Attack `protobuf:"bytes,2,opt,name=attack,proto3,oneof"`
+}
+
+type BattleRequest_Heartbeat_ struct {
+	Heartbeat *BattleRequest_Heartbeat `protobuf:"bytes,3,opt,name=heartbeat,proto3,oneof"`
+}
+
+type BattleRequest_Chat_ struct {
+	Chat *BattleRequest_Chat `protobuf:"bytes,4,opt,name=chat,proto3,oneof"`
+}
+
+func (*BattleRequest_PlaceFleet_) isBattleRequest_Move() {}
+
+func (*BattleRequest_Attack_) isBattleRequest_Move() {}
+
+func (*BattleRequest_Heartbeat_) isBattleRequest_Move() {}
+
+func (*BattleRequest_Chat_) isBattleRequest_Move() {}
+
+type BattleResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//	*BattleResponse_Ready_
+	//	*BattleResponse_Shot_
+	//	*BattleResponse_End_
+	//	*BattleResponse_Chat_
+	//	*BattleResponse_Error_
+	Event isBattleResponse_Event `protobuf_oneof:"event"`
+}
+
+func (x *BattleResponse) Reset() {
+	*x = BattleResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BattleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BattleResponse) ProtoMessage() {}
+
+func (x *BattleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BattleResponse.ProtoReflect.Descriptor instead.
+func (*BattleResponse) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{25}
+}
+
+func (m *BattleResponse) GetEvent() isBattleResponse_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *BattleResponse) GetReady() *BattleResponse_Ready {
+	if x, ok := x.GetEvent().(*BattleResponse_Ready_); ok {
+		return x.Ready
+	}
+	return nil
+}
+
+func (x *BattleResponse) GetShot() *BattleResponse_Shot {
+	if x, ok := x.GetEvent().(*BattleResponse_Shot_); ok {
+		return x.Shot
+	}
+	return nil
+}
+
+func (x *BattleResponse) GetEnd() *BattleResponse_End {
+	if x, ok := x.GetEvent().(*BattleResponse_End_); ok {
+		return x.End
+	}
+	return nil
+}
+
+func (x *BattleResponse) GetChat() *BattleResponse_Chat {
+	if x, ok := x.GetEvent().(*BattleResponse_Chat_); ok {
+		return x.Chat
+	}
+	return nil
+}
+
+func (x *BattleResponse) GetError() *BattleResponse_Error {
+	if x, ok := x.GetEvent().(*BattleResponse_Error_); ok {
+		return x.Error
+	}
+	return nil
+}
+
+type isBattleResponse_Event interface {
+	isBattleResponse_Event()
+}
+
+type BattleResponse_Ready_ struct {
+	Ready *BattleResponse_Ready `protobuf:"bytes,1,opt,name=ready,proto3,oneof"`
+}
+
+type BattleResponse_Shot_ struct {
+	Shot *BattleResponse_Shot `protobuf:"bytes,2,opt,name=shot,proto3,oneof"`
+}
+
+type BattleResponse_End_ struct {
+	End *BattleResponse_End `protobuf:"bytes,3,opt,name=end,proto3,oneof"`
+}
+
+type BattleResponse_Chat_ struct {
+	Chat *BattleResponse_Chat `protobuf:"bytes,4,opt,name=chat,proto3,oneof"`
+}
+
+type BattleResponse_Error_ struct {
+	Error *BattleResponse_Error `protobuf:"bytes,5,opt,name=error,proto3,oneof"`
+}
+
+func (*BattleResponse_Ready_) isBattleResponse_Event() {}
+
+func (*BattleResponse_Shot_) isBattleResponse_Event() {}
+
+func (*BattleResponse_End_) isBattleResponse_Event() {}
+
+func (*BattleResponse_Chat_) isBattleResponse_Event() {}
+
+func (*BattleResponse_Error_) isBattleResponse_Event() {}
+
+type BattleRequest_PlaceFleet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ships []*Ship `protobuf:"bytes,1,rep,name=ships,proto3" json:"ships,omitempty"`
+}
+
+func (x *BattleRequest_PlaceFleet) Reset() {
+	*x = BattleRequest_PlaceFleet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BattleRequest_PlaceFleet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BattleRequest_PlaceFleet) ProtoMessage() {}
+
+func (x *BattleRequest_PlaceFleet) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BattleRequest_PlaceFleet.ProtoReflect.Descriptor instead.
+func (*BattleRequest_PlaceFleet) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{24, 0}
+}
+
+func (x *BattleRequest_PlaceFleet) GetShips() []*Ship {
+	if x != nil {
+		return x.Ships
+	}
+	return nil
+}
+
+type BattleRequest_Attack struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X int32 `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y int32 `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+}
+
+func (x *BattleRequest_Attack) Reset() {
+	*x = BattleRequest_Attack{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BattleRequest_Attack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BattleRequest_Attack) ProtoMessage() {}
+
+func (x *BattleRequest_Attack) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BattleRequest_Attack.ProtoReflect.Descriptor instead.
+func (*BattleRequest_Attack) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{24, 1}
+}
+
+func (x *BattleRequest_Attack) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *BattleRequest_Attack) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+type BattleRequest_Heartbeat struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *BattleRequest_Heartbeat) Reset() {
+	*x = BattleRequest_Heartbeat{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BattleRequest_Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BattleRequest_Heartbeat) ProtoMessage() {}
+
+func (x *BattleRequest_Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BattleRequest_Heartbeat.ProtoReflect.Descriptor instead.
+func (*BattleRequest_Heartbeat) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{24, 2}
+}
+
+type BattleRequest_Chat struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+}
+
+func (x *BattleRequest_Chat) Reset() {
+	*x = BattleRequest_Chat{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BattleRequest_Chat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BattleRequest_Chat) ProtoMessage() {}
+
+func (x *BattleRequest_Chat) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BattleRequest_Chat.ProtoReflect.Descriptor instead.
+func (*BattleRequest_Chat) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{24, 3}
+}
+
+func (x *BattleRequest_Chat) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// Ready tells that both fleets are placed and the battle begins.
+type BattleResponse_Ready struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	First bool `protobuf:"varint,1,opt,name=first,proto3" json:"first,omitempty"` // the user shoots first
+}
+
+func (x *BattleResponse_Ready) Reset() {
+	*x = BattleResponse_Ready{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BattleResponse_Ready) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BattleResponse_Ready) ProtoMessage() {}
+
+func (x *BattleResponse_Ready) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BattleResponse_Ready.ProtoReflect.Descriptor instead.
+func (*BattleResponse_Ready) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{25, 0}
+}
+
+func (x *BattleResponse_Ready) GetFirst() bool {
+	if x != nil {
+		return x.First
+	}
+	return false
+}
+
+type BattleResponse_Shot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Attacker string `protobuf:"bytes,1,opt,name=attacker,proto3" json:"attacker,omitempty"`
+	X        int32  `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y        int32  `protobuf:"varint,3,opt,name=y,proto3" json:"y,omitempty"`
+	Hit      bool   `protobuf:"varint,4,opt,name=hit,proto3" json:"hit,omitempty"`
+	Destroy  bool   `protobuf:"varint,5,opt,name=destroy,proto3" json:"destroy,omitempty"`
+}
+
+func (x *BattleResponse_Shot) Reset() {
+	*x = BattleResponse_Shot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BattleResponse_Shot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BattleResponse_Shot) ProtoMessage() {}
+
+func (x *BattleResponse_Shot) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BattleResponse_Shot.ProtoReflect.Descriptor instead.
+func (*BattleResponse_Shot) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{25, 1}
+}
+
+func (x *BattleResponse_Shot) GetAttacker() string {
+	if x != nil {
+		return x.Attacker
+	}
+	return ""
+}
+
+func (x *BattleResponse_Shot) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *BattleResponse_Shot) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *BattleResponse_Shot) GetHit() bool {
+	if x != nil {
+		return x.Hit
+	}
+	return false
+}
+
+func (x *BattleResponse_Shot) GetDestroy() bool {
+	if x != nil {
+		return x.Destroy
+	}
+	return false
+}
+
+// End carries the last shot, if the game ended with one.
+type BattleResponse_End struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Shot   *BattleResponse_Shot `protobuf:"bytes,1,opt,name=shot,proto3" json:"shot,omitempty"`
+	Winner string               `protobuf:"bytes,2,opt,name=winner,proto3" json:"winner,omitempty"`
+	Reason string               `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *BattleResponse_End) Reset() {
+	*x = BattleResponse_End{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BattleResponse_End) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BattleResponse_End) ProtoMessage() {}
+
+func (x *BattleResponse_End) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BattleResponse_End.ProtoReflect.Descriptor instead.
+func (*BattleResponse_End) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{25, 2}
+}
+
+func (x *BattleResponse_End) GetShot() *BattleResponse_Shot {
+	if x != nil {
+		return x.Shot
+	}
+	return nil
+}
+
+func (x *BattleResponse_End) GetWinner() string {
+	if x != nil {
+		return x.Winner
+	}
+	return ""
+}
+
+func (x *BattleResponse_End) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type BattleResponse_Chat struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	Text string `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+}
+
+func (x *BattleResponse_Chat) Reset() {
+	*x = BattleResponse_Chat{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BattleResponse_Chat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BattleResponse_Chat) ProtoMessage() {}
+
+func (x *BattleResponse_Chat) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BattleResponse_Chat.ProtoReflect.Descriptor instead.
+func (*BattleResponse_Chat) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{25, 3}
+}
+
+func (x *BattleResponse_Chat) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *BattleResponse_Chat) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// Error rejects a move of the user, the battle goes on.
+type BattleResponse_Error struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Move    string `protobuf:"bytes,1,opt,name=move,proto3" json:"move,omitempty"` // the name of the move field of the request, e.g. "attack"
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *BattleResponse_Error) Reset() {
+	*x = BattleResponse_Error{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_battleship_v1_battleship_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BattleResponse_Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BattleResponse_Error) ProtoMessage() {}
+
+func (x *BattleResponse_Error) ProtoReflect() protoreflect.Message {
+	mi := &file_battleship_v1_battleship_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BattleResponse_Error.ProtoReflect.Descriptor instead.
+func (*BattleResponse_Error) Descriptor() ([]byte, []int) {
+	return file_battleship_v1_battleship_proto_rawDescGZIP(), []int{25, 4}
+}
+
+func (x *BattleResponse_Error) GetMove() string {
+	if x != nil {
+		return x.Move
+	}
+	return ""
+}
+
+func (x *BattleResponse_Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_battleship_v1_battleship_proto protoreflect.FileDescriptor
+
+var file_battleship_v1_battleship_proto_rawDesc = []byte{
+	0x0a, 0x1e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2f, 0x76, 0x31, 0x2f,
+	0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x0d, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x1a,
+	0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0x49, 0x0a, 0x0f, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x28, 0x0a, 0x10, 0x52,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x46, 0x0a, 0x0c, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x25, 0x0a,
+	0x0d, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x0f, 0x0a, 0x0d, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x10, 0x0a, 0x0e, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x13, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x30, 0x0a, 0x12,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x70, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x22, 0x3d,
+	0x0a, 0x0f, 0x4a, 0x6f, 0x69, 0x6e, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x2a, 0x0a, 0x11, 0x63, 0x72, 0x65, 0x61, 0x74, 0x6f, 0x72, 0x5f, 0x75, 0x73, 0x65,
+	0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x6f, 0x72, 0x55, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x12, 0x0a,
+	0x10, 0x4a, 0x6f, 0x69, 0x6e, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x12, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x2f, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x6d,
+	0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x22, 0x10, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x47, 0x61, 0x6d,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x11, 0x0a, 0x0f, 0x44, 0x65, 0x6c, 0x47,
+	0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x31, 0x0a, 0x12, 0x47,
+	0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x59,
+	0x0a, 0x13, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a,
+	0x04, 0x77, 0x69, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x77, 0x69, 0x6e,
+	0x73, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x73, 0x73, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x6c, 0x6f, 0x73, 0x73, 0x65, 0x73, 0x22, 0x61, 0x0a, 0x11, 0x47, 0x65, 0x74,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70,
+	0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x22, 0xca, 0x02, 0x0a,
+	0x05, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x6f, 0x73, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c,
+	0x6f, 0x73, 0x65, 0x72, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12,
+	0x35, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65,
+	0x6e, 0x64, 0x65, 0x64, 0x41, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x12, 0x2e, 0x0a, 0x13,
+	0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x65,
+	0x6c, 0x74, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x77, 0x69, 0x6e, 0x6e, 0x65,
+	0x72, 0x52, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x12,
+	0x6c, 0x6f, 0x73, 0x65, 0x72, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x65, 0x6c,
+	0x74, 0x61, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x6c, 0x6f, 0x73, 0x65, 0x72, 0x52,
+	0x61, 0x74, 0x69, 0x6e, 0x67, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x6e,
+	0x64, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x65, 0x6e, 0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x5a, 0x0a, 0x12, 0x47, 0x65, 0x74,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x2e, 0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31,
+	0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x48, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x61,
+	0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x22,
+	0x87, 0x01, 0x0a, 0x10, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x73, 0x65, 0x72,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65,
+	0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a,
+	0x04, 0x77, 0x69, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x77, 0x69, 0x6e,
+	0x73, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x73, 0x73, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x6c, 0x6f, 0x73, 0x73, 0x65, 0x73, 0x22, 0x82, 0x01, 0x0a, 0x16, 0x47, 0x65,
+	0x74, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68,
+	0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x62, 0x6f, 0x61, 0x72,
+	0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x79, 0x5f, 0x72, 0x61, 0x6e, 0x6b,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6d, 0x79, 0x52, 0x61, 0x6e, 0x6b, 0x22, 0x23,
+	0x0a, 0x05, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x01, 0x79, 0x22, 0x32, 0x0a, 0x04, 0x53, 0x68, 0x69, 0x70, 0x12, 0x2a, 0x0a, 0x05, 0x63,
+	0x65, 0x6c, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x74,
+	0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74,
+	0x52, 0x05, 0x63, 0x65, 0x6c, 0x6c, 0x73, 0x22, 0xab, 0x03, 0x0a, 0x0d, 0x42, 0x61, 0x74, 0x74,
+	0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x4a, 0x0a, 0x0b, 0x70, 0x6c, 0x61,
+	0x63, 0x65, 0x5f, 0x66, 0x6c, 0x65, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27,
+	0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x42,
+	0x61, 0x74, 0x74, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x50, 0x6c, 0x61,
+	0x63, 0x65, 0x46, 0x6c, 0x65, 0x65, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x63, 0x65,
+	0x46, 0x6c, 0x65, 0x65, 0x74, 0x12, 0x3d, 0x0a, 0x06, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68,
+	0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x06, 0x61, 0x74,
+	0x74, 0x61, 0x63, 0x6b, 0x12, 0x46, 0x0a, 0x09, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65,
+	0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x48,
+	0x00, 0x52, 0x09, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x12, 0x37, 0x0a, 0x04,
+	0x63, 0x68, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x62, 0x61, 0x74,
+	0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x74, 0x6c,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x48, 0x00, 0x52,
+	0x04, 0x63, 0x68, 0x61, 0x74, 0x1a, 0x37, 0x0a, 0x0a, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x46, 0x6c,
+	0x65, 0x65, 0x74, 0x12, 0x29, 0x0a, 0x05, 0x73, 0x68, 0x69, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x68, 0x69, 0x70, 0x52, 0x05, 0x73, 0x68, 0x69, 0x70, 0x73, 0x1a, 0x24,
+	0x0a, 0x06, 0x41, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x01, 0x79, 0x1a, 0x0b, 0x0a, 0x09, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61,
+	0x74, 0x1a, 0x1a, 0x0a, 0x04, 0x43, 0x68, 0x61, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x42, 0x06, 0x0a,
+	0x04, 0x6d, 0x6f, 0x76, 0x65, 0x22, 0x9f, 0x05, 0x0a, 0x0e, 0x42, 0x61, 0x74, 0x74, 0x6c, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x05, 0x72, 0x65, 0x61, 0x64,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65,
+	0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x79, 0x48, 0x00, 0x52, 0x05,
+	0x72, 0x65, 0x61, 0x64, 0x79, 0x12, 0x38, 0x0a, 0x04, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70,
+	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x2e, 0x53, 0x68, 0x6f, 0x74, 0x48, 0x00, 0x52, 0x04, 0x73, 0x68, 0x6f, 0x74, 0x12,
+	0x35, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x62,
+	0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74,
+	0x74, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x45, 0x6e, 0x64, 0x48,
+	0x00, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x38, 0x0a, 0x04, 0x63, 0x68, 0x61, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69,
+	0x70, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x48, 0x00, 0x52, 0x04, 0x63, 0x68, 0x61, 0x74,
+	0x12, 0x3b, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x23, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e,
+	0x42, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x1a, 0x1d, 0x0a,
+	0x05, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x72, 0x73, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x69, 0x72, 0x73, 0x74, 0x1a, 0x6a, 0x0a, 0x04,
+	0x53, 0x68, 0x6f, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x74, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x72,
+	0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x78, 0x12, 0x0c,
+	0x0a, 0x01, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x68, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x68, 0x69, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x64, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x64, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x1a, 0x6d, 0x0a, 0x03, 0x45, 0x6e, 0x64, 0x12,
+	0x36, 0x0a, 0x04, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e,
+	0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61,
+	0x74, 0x74, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x53, 0x68, 0x6f,
+	0x74, 0x52, 0x04, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x1a, 0x2e, 0x0a, 0x04, 0x43, 0x68, 0x61, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66,
+	0x72, 0x6f, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x1a, 0x35, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6d, 0x6f, 0x76, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x07,
+	0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x32, 0xe5, 0x01, 0x0a, 0x0b, 0x41, 0x75, 0x74, 0x68,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4b, 0x0a, 0x08, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x12, 0x1e, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x1b, 0x2e,
+	0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f,
+	0x67, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x62, 0x61, 0x74,
+	0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x06, 0x4c, 0x6f, 0x67, 0x6f,
+	0x75, 0x74, 0x12, 0x1c, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1d, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32,
+	0x9a, 0x05, 0x0a, 0x0b, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x51, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x2e,
+	0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x21, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x4b, 0x0a, 0x08, 0x4a, 0x6f, 0x69, 0x6e, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x1e,
+	0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4a,
+	0x6f, 0x69, 0x6e, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4a,
+	0x6f, 0x69, 0x6e, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4e, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x1f, 0x2e, 0x62,
+	0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e,
+	0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x48, 0x0a, 0x07, 0x44, 0x65, 0x6c, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x2e, 0x62, 0x61, 0x74,
+	0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x47, 0x61,
+	0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x62, 0x61, 0x74, 0x74,
+	0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x47, 0x61, 0x6d,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a, 0x0b, 0x47, 0x65, 0x74,
+	0x55, 0x73, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x12, 0x21, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c,
+	0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72,
+	0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x62, 0x61,
+	0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x55,
+	0x73, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x51, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x20, 0x2e,
+	0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x21, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x5d, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x62,
+	0x6f, 0x61, 0x72, 0x64, 0x12, 0x24, 0x2e, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69,
+	0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x62, 0x6f,
+	0x61, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x62, 0x61, 0x74,
+	0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x49, 0x0a, 0x06, 0x42, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x12, 0x1c, 0x2e, 0x62, 0x61,
+	0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x74,
+	0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x62, 0x61, 0x74, 0x74,
+	0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x74, 0x6c, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01, 0x42, 0x33, 0x5a, 0x31,
+	0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x2d, 0x73, 0x68, 0x69, 0x70, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69,
+	0x70, 0x2f, 0x76, 0x31, 0x3b, 0x62, 0x61, 0x74, 0x74, 0x6c, 0x65, 0x73, 0x68, 0x69, 0x70, 0x76,
+	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_battleship_v1_battleship_proto_rawDescOnce sync.Once
+	file_battleship_v1_battleship_proto_rawDescData = file_battleship_v1_battleship_proto_rawDesc
+)
+
+func file_battleship_v1_battleship_proto_rawDescGZIP() []byte {
+	file_battleship_v1_battleship_proto_rawDescOnce.Do(func() {
+		file_battleship_v1_battleship_proto_rawDescData = protoimpl.X.CompressGZIP(file_battleship_v1_battleship_proto_rawDescData)
+	})
+	return file_battleship_v1_battleship_proto_rawDescData
+}
+
+var file_battleship_v1_battleship_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
+var file_battleship_v1_battleship_proto_goTypes = []any{
+	(*RegisterRequest)(nil),          // 0: battleship.v1.RegisterRequest
+	(*RegisterResponse)(nil),         // 1: battleship.v1.RegisterResponse
+	(*LoginRequest)(nil),             // 2: battleship.v1.LoginRequest
+	(*LoginResponse)(nil),            // 3: battleship.v1.LoginResponse
+	(*LogoutRequest)(nil),            // 4: battleship.v1.LogoutRequest
+	(*LogoutResponse)(nil),           // 5: battleship.v1.LogoutResponse
+	(*CreateGameRequest)(nil),        // 6: battleship.v1.CreateGameRequest
+	(*CreateGameResponse)(nil),       // 7: battleship.v1.CreateGameResponse
+	(*JoinGameRequest)(nil),          // 8: battleship.v1.JoinGameRequest
+	(*JoinGameResponse)(nil),         // 9: battleship.v1.JoinGameResponse
+	(*ListGamesRequest)(nil),         // 10: battleship.v1.ListGamesRequest
+	(*ListGamesResponse)(nil),        // 11: battleship.v1.ListGamesResponse
+	(*DelGameRequest)(nil),           // 12: battleship.v1.DelGameRequest
+	(*DelGameResponse)(nil),          // 13: battleship.v1.DelGameResponse
+	(*GetUserStatRequest)(nil),       // 14: battleship.v1.GetUserStatRequest
+	(*GetUserStatResponse)(nil),      // 15: battleship.v1.GetUserStatResponse
+	(*GetHistoryRequest)(nil),        // 16: battleship.v1.GetHistoryRequest
+	(*Match)(nil),                    // 17: battleship.v1.Match
+	(*GetHistoryResponse)(nil),       // 18: battleship.v1.GetHistoryResponse
+	(*GetLeaderboardRequest)(nil),    // 19: battleship.v1.GetLeaderboardRequest
+	(*LeaderboardEntry)(nil),         // 20: battleship.v1.LeaderboardEntry
+	(*GetLeaderboardResponse)(nil),   // 21: battleship.v1.GetLeaderboardResponse
+	(*Point)(nil),                    // 22: battleship.v1.Point
+	(*Ship)(nil),                     // 23: battleship.v1.Ship
+	(*BattleRequest)(nil),            // 24: battleship.v1.BattleRequest
+	(*BattleResponse)(nil),           // 25: battleship.v1.BattleResponse
+	(*BattleRequest_PlaceFleet)(nil), // 26: battleship.v1.BattleRequest.PlaceFleet
+	(*BattleRequest_Attack)(nil),     // 27: battleship.v1.BattleRequest.Attack
+	(*BattleRequest_Heartbeat)(nil),  // 28: battleship.v1.BattleRequest.Heartbeat
+	(*BattleRequest_Chat)(nil),       // 29: battleship.v1.BattleRequest.Chat
+	(*BattleResponse_Ready)(nil),     // 30: battleship.v1.BattleResponse.Ready
+	(*BattleResponse_Shot)(nil),      // 31: battleship.v1.BattleResponse.Shot
+	(*BattleResponse_End)(nil),       // 32: battleship.v1.BattleResponse.End
+	(*BattleResponse_Chat)(nil),      // 33: battleship.v1.BattleResponse.Chat
+	(*BattleResponse_Error)(nil),     // 34: battleship.v1.BattleResponse.Error
+	(*timestamppb.Timestamp)(nil),    // 35: google.protobuf.Timestamp
+}
+var file_battleship_v1_battleship_proto_depIdxs = []int32{
+	35, // 0: battleship.v1.Match.started_at:type_name -> google.protobuf.Timestamp
+	35, // 1: battleship.v1.Match.ended_at:type_name -> google.protobuf.Timestamp
+	17, // 2: battleship.v1.GetHistoryResponse.matches:type_name -> battleship.v1.Match
+	20, // 3: battleship.v1.GetLeaderboardResponse.entries:type_name -> battleship.v1.LeaderboardEntry
+	22, // 4: battleship.v1.Ship.cells:type_name -> battleship.v1.Point
+	26, // 5: battleship.v1.BattleRequest.place_fleet:type_name -> battleship.v1.BattleRequest.PlaceFleet
+	27, // 6: battleship.v1.BattleRequest.attack:type_name -> battleship.v1.BattleRequest.Attack
+	28, // 7: battleship.v1.BattleRequest.heartbeat:type_name -> battleship.v1.BattleRequest.Heartbeat
+	29, // 8: battleship.v1.BattleRequest.chat:type_name -> battleship.v1.BattleRequest.Chat
+	30, // 9: battleship.v1.BattleResponse.ready:type_name -> battleship.v1.BattleResponse.Ready
+	31, // 10: battleship.v1.BattleResponse.shot:type_name -> battleship.v1.BattleResponse.Shot
+	32, // 11: battleship.v1.BattleResponse.end:type_name -> battleship.v1.BattleResponse.End
+	33, // 12: battleship.v1.BattleResponse.chat:type_name -> battleship.v1.BattleResponse.Chat
+	34, // 13: battleship.v1.BattleResponse.error:type_name -> battleship.v1.BattleResponse.Error
+	23, // 14: battleship.v1.BattleRequest.PlaceFleet.ships:type_name -> battleship.v1.Ship
+	31, // 15: battleship.v1.BattleResponse.End.shot:type_name -> battleship.v1.BattleResponse.Shot
+	0,  // 16: battleship.v1.AuthService.Register:input_type -> battleship.v1.RegisterRequest
+	2,  // 17: battleship.v1.AuthService.Login:input_type -> battleship.v1.LoginRequest
+	4,  // 18: battleship.v1.AuthService.Logout:input_type -> battleship.v1.LogoutRequest
+	6,  // 19: battleship.v1.GameService.CreateGame:input_type -> battleship.v1.CreateGameRequest
+	8,  // 20: battleship.v1.GameService.JoinGame:input_type -> battleship.v1.JoinGameRequest
+	10, // 21: battleship.v1.GameService.ListGames:input_type -> battleship.v1.ListGamesRequest
+	12, // 22: battleship.v1.GameService.DelGame:input_type -> battleship.v1.DelGameRequest
+	14, // 23: battleship.v1.GameService.GetUserStat:input_type -> battleship.v1.GetUserStatRequest
+	16, // 24: battleship.v1.GameService.GetHistory:input_type -> battleship.v1.GetHistoryRequest
+	19, // 25: battleship.v1.GameService.GetLeaderboard:input_type -> battleship.v1.GetLeaderboardRequest
+	24, // 26: battleship.v1.GameService.Battle:input_type -> battleship.v1.BattleRequest
+	1,  // 27: battleship.v1.AuthService.Register:output_type -> battleship.v1.RegisterResponse
+	3,  // 28: battleship.v1.AuthService.Login:output_type -> battleship.v1.LoginResponse
+	5,  // 29: battleship.v1.AuthService.Logout:output_type -> battleship.v1.LogoutResponse
+	7,  // 30: battleship.v1.GameService.CreateGame:output_type -> battleship.v1.CreateGameResponse
+	9,  // 31: battleship.v1.GameService.JoinGame:output_type -> battleship.v1.JoinGameResponse
+	11, // 32: battleship.v1.GameService.ListGames:output_type -> battleship.v1.ListGamesResponse
+	13, // 33: battleship.v1.GameService.DelGame:output_type -> battleship.v1.DelGameResponse
+	15, // 34: battleship.v1.GameService.GetUserStat:output_type -> battleship.v1.GetUserStatResponse
+	18, // 35: battleship.v1.GameService.GetHistory:output_type -> battleship.v1.GetHistoryResponse
+	21, // 36: battleship.v1.GameService.GetLeaderboard:output_type -> battleship.v1.GetLeaderboardResponse
+	25, // 37: battleship.v1.GameService.Battle:output_type -> battleship.v1.BattleResponse
+	27, // [27:38] is the sub-list for method output_type
+	16, // [16:27] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_battleship_v1_battleship_proto_init() }
+func file_battleship_v1_battleship_proto_init() {
+	if File_battleship_v1_battleship_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_battleship_v1_battleship_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*RegisterRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*RegisterResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*LoginRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*LoginResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*LogoutRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*LogoutResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateGameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateGameResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*JoinGameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*JoinGameResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*ListGamesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*ListGamesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*DelGameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*DelGameResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*GetUserStatRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*GetUserStatResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*GetHistoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*Match); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*GetHistoryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*GetLeaderboardRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[20].Exporter = func(v any, i int) any {
+			switch v := v.(*LeaderboardEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[21].Exporter = func(v any, i int) any {
+			switch v := v.(*GetLeaderboardResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[22].Exporter = func(v any, i int) any {
+			switch v := v.(*Point); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[23].Exporter = func(v any, i int) any {
+			switch v := v.(*Ship); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[24].Exporter = func(v any, i int) any {
+			switch v := v.(*BattleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[25].Exporter = func(v any, i int) any {
+			switch v := v.(*BattleResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[26].Exporter = func(v any, i int) any {
+			switch v := v.(*BattleRequest_PlaceFleet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[27].Exporter = func(v any, i int) any {
+			switch v := v.(*BattleRequest_Attack); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[28].Exporter = func(v any, i int) any {
+			switch v := v.(*BattleRequest_Heartbeat); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[29].Exporter = func(v any, i int) any {
+			switch v := v.(*BattleRequest_Chat); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[30].Exporter = func(v any, i int) any {
+			switch v := v.(*BattleResponse_Ready); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[31].Exporter = func(v any, i int) any {
+			switch v := v.(*BattleResponse_Shot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[32].Exporter = func(v any, i int) any {
+			switch v := v.(*BattleResponse_End); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[33].Exporter = func(v any, i int) any {
+			switch v := v.(*BattleResponse_Chat); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_battleship_v1_battleship_proto_msgTypes[34].Exporter = func(v any, i int) any {
+			switch v := v.(*BattleResponse_Error); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_battleship_v1_battleship_proto_msgTypes[24].OneofWrappers = []any{
+		(*BattleRequest_PlaceFleet_)(nil),
+		(*BattleRequest_Attack_)(nil),
+		(*BattleRequest_Heartbeat_)(nil),
+		(*BattleRequest_Chat_)(nil),
+	}
+	file_battleship_v1_battleship_proto_msgTypes[25].OneofWrappers = []any{
+		(*BattleResponse_Ready_)(nil),
+		(*BattleResponse_Shot_)(nil),
+		(*BattleResponse_End_)(nil),
+		(*BattleResponse_Chat_)(nil),
+		(*BattleResponse_Error_)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_battleship_v1_battleship_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   35,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_battleship_v1_battleship_proto_goTypes,
+		DependencyIndexes: file_battleship_v1_battleship_proto_depIdxs,
+		MessageInfos:      file_battleship_v1_battleship_proto_msgTypes,
+	}.Build()
+	File_battleship_v1_battleship_proto = out.File
+	file_battleship_v1_battleship_proto_rawDesc = nil
+	file_battleship_v1_battleship_proto_goTypes = nil
+	file_battleship_v1_battleship_proto_depIdxs = nil
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"battle-ship_server/internal/config"
+	"battle-ship_server/internal/port/rabbitmq"
+	"battle-ship_server/internal/service/game"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+)
+
+const usage = `Usage: battleship [command]
+
+Without a command the server is started.
+
+Commands:
+  recompute-ratings     reset all ratings and replay the recorded results with the configured rating system
+  migrate up            apply all pending migrations of the postgres or sqlite storage
+  migrate down [N]      roll back the last N applied migrations, 1 by default
+  migrate status        list the migrations and the version of the database schema
+  dlq list              count the dead-lettered requests of every queue, and the ones dead-lettered
+                        by the running server since it started if its metrics are enabled
+  dlq show QUEUE [N]    print the first N dead-lettered requests of the queue, 10 by default
+  dlq replay QUEUE [N]  send the first N dead-lettered requests back to the queue, all of them by default
+  dlq purge QUEUE       delete the dead-lettered requests of the queue
+`
+
+// runCommand runs a maintenance command instead of the server and returns the exit code
+func runCommand(args []string, cfg *config.Config, log *slog.Logger) int {
+	switch args[0] {
+	case "recompute-ratings":
+		return recomputeRatings(cfg, log)
+	case "migrate":
+		return migrateSchema(args[1:], cfg, log)
+	case "dlq":
+		return deadLetters(args[1:], cfg, log)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+}
+
+func recomputeRatings(cfg *config.Config, log *slog.Logger) int {
+	storage, err := setupStorage(cfg)
+	if err != nil {
+		log.Error("Failed to open the storage", slog.String("error", err.Error()))
+		return 1
+	}
+	defer storage.Close()
+
+	games := game.New(storage, log, setupRating(cfg.Rating), setupTimeouts(cfg.Game), setupMatchmaking(cfg.Matchmaking))
+	defer games.Close()
+	err = games.RecomputeRatings(context.Background())
+	if err != nil {
+		return 1
+	}
+	return 0
+}
+
+func migrateSchema(args []string, cfg *config.Config, log *slog.Logger) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	steps := 1
+	if args[0] == "down" && len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			fmt.Fprint(os.Stderr, usage)
+			return 2
+		}
+		steps = n
+	}
+
+	migrator, err := openMigrator(cfg)
+	if err != nil {
+		log.Error("Failed to open the database", slog.String("driver", cfg.Storage.Driver), slog.String("error", err.Error()))
+		return 1
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		n, err := migrator.Up()
+		if err != nil {
+			log.Error("Failed to apply migrations", slog.String("error", err.Error()), slog.Int("applied", n))
+			return 1
+		}
+		log.Info("Migrations applied", slog.Int("applied", n), slog.Int("version", migrator.Latest()))
+	case "down":
+		n, err := migrator.Down(steps)
+		if err != nil {
+			log.Error("Failed to roll back migrations", slog.String("error", err.Error()), slog.Int("rolled_back", n))
+			return 1
+		}
+		log.Info("Migrations rolled back", slog.Int("rolled_back", n))
+	case "status":
+		version, err := migrator.Version()
+		if err != nil {
+			log.Error("Failed to get the schema version", slog.String("error", err.Error()))
+			return 1
+		}
+		migrations, err := migrator.Status()
+		if err != nil {
+			log.Error("Failed to get the migrations", slog.String("error", err.Error()))
+			return 1
+		}
+
+		fmt.Printf("Schema version: %d, latest known: %d\n", version, migrator.Latest())
+		for _, m := range migrations {
+			applied := "pending"
+			if !m.AppliedAt.IsZero() {
+				applied = "applied " + m.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d %-30s %s\n", m.Version, m.Name, applied)
+		}
+		if version > migrator.Latest() {
+			fmt.Println("The schema is newer than this server, upgrade the server before starting it")
+		}
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	return 0
+}
+
+func deadLetters(args []string, cfg *config.Config, log *slog.Logger) int {
+	if len(args) == 0 || args[0] != "list" && len(args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	limit := 0
+	if len(args) > 2 && (args[0] == "show" || args[0] == "replay") {
+		n, err := strconv.Atoi(args[2])
+		if err != nil || n < 1 {
+			fmt.Fprint(os.Stderr, usage)
+			return 2
+		}
+		limit = n
+	}
+
+	dl, err := rabbitmq.OpenDeadLetters(rabbitmqURL(cfg.RabbitMQ))
+	if err != nil {
+		log.Error("Failed to connect to rabbitmq", slog.String("error", err.Error()))
+		return 1
+	}
+	defer dl.Close()
+
+	switch args[0] {
+	case "list":
+		var counted map[string]int64
+		if cfg.Metrics.Enabled {
+			counted, err = deadLettered(cfg.Metrics)
+			if err != nil {
+				log.Warn("Failed to read the metrics of the server", slog.String("error", err.Error()))
+			}
+		}
+		fmt.Printf("%-22s %-8s %s\n", "QUEUE", "IN DLQ", "DEAD-LETTERED SINCE START")
+		for _, queue := range dl.Queues() {
+			n, err := dl.Count(queue)
+			if err != nil {
+				log.Error("Failed to count the dead letters", slog.String("queue", queue), slog.String("error", err.Error()))
+				return 1
+			}
+			since := "-"
+			if counted != nil {
+				since = strconv.FormatInt(counted[queue], 10)
+			}
+			fmt.Printf("%-22s %-8d %s\n", queue, n, since)
+		}
+	case "show":
+		if limit == 0 {
+			limit = 10
+		}
+		letters, err := dl.Peek(args[1], limit)
+		if err != nil {
+			log.Error("Failed to read the dead letters", slog.String("queue", args[1]), slog.String("error", err.Error()))
+			return 1
+		}
+		for _, l := range letters {
+			fmt.Printf("%s %s reason=%s count=%d retries=%d correlation_id=%s\n%s\n",
+				l.Time.Local().Format("2006-01-02 15:04:05"), l.Queue, l.Reason, l.Count, l.Retries, l.CorrelationID, l.Body)
+		}
+	case "replay":
+		n, err := dl.Replay(args[1], limit)
+		if err != nil {
+			log.Error("Failed to replay the dead letters", slog.String("queue", args[1]), slog.String("error", err.Error()), slog.Int("replayed", n))
+			return 1
+		}
+		log.Info("Dead letters replayed", slog.String("queue", args[1]), slog.Int("replayed", n))
+	case "purge":
+		n, err := dl.Purge(args[1])
+		if err != nil {
+			log.Error("Failed to purge the dead letters", slog.String("queue", args[1]), slog.String("error", err.Error()))
+			return 1
+		}
+		log.Info("Dead letters purged", slog.String("queue", args[1]), slog.Int("purged", n))
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	return 0
+}
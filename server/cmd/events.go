@@ -0,0 +1,59 @@
+package main
+
+import (
+	"battle-ship_server/internal/service/game"
+	"expvar"
+	"log/slog"
+)
+
+// droppedEvents counts the events of the spectators the ports were too slow to take, by the name of the port.
+// It is published by expvar under the name "events_dropped".
+var droppedEvents = expvar.NewMap("events_dropped")
+
+// portBuffer is how many events may wait for a port before the events of the spectators are dropped
+// and the ones of the players wait for the port
+const portBuffer = 256
+
+// portGame is the game service as seen by one of the ports, with a channel of the events of its own
+type portGame struct {
+	*game.Service
+	events <-chan game.Event
+}
+
+func (g portGame) Events() <-chan game.Event {
+	return g.events
+}
+
+// shareEvents copies every event of the game service to each of the named ports.
+// The players of a port get nothing from the events of the others, as they are not connected there.
+// An event of a player is never dropped, the player would wait for it forever: once the buffer of a port is full,
+// the game service waits for the port. Only the events of the spectators are dropped then.
+func shareEvents(g *game.Service, log *slog.Logger, names ...string) []portGame {
+	outs := make([]chan game.Event, len(names))
+	ports := make([]portGame, len(names))
+	for i := range outs {
+		outs[i] = make(chan game.Event, portBuffer)
+		ports[i] = portGame{Service: g, events: outs[i]}
+	}
+
+	go func() {
+		for e := range g.Events() {
+			for i, out := range outs {
+				if e.To != "" {
+					out <- e
+					continue
+				}
+				select {
+				case out <- e:
+				default:
+					droppedEvents.Add(names[i], 1)
+					log.Warn("Port is too slow, event of the spectators dropped", slog.String("port", names[i]), slog.String("game_id", e.GameID))
+				}
+			}
+		}
+		for _, out := range outs {
+			close(out)
+		}
+	}()
+	return ports
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"battle-ship_server/internal/config"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// metricsPath is where the expvar metrics are served, the path expvar uses on the default mux
+const metricsPath = "/debug/vars"
+
+// serveMetrics serves the expvar metrics of the server on their own address, away from the gateway:
+// the counters of the AMQP endpoints under "rpc" and the events of the spectators the slow ports missed under "events_dropped"
+func serveMetrics(cfg config.MetricsConfig, log *slog.Logger) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, expvar.Handler())
+	srv := &http.Server{
+		Addr:              cfg.Address,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		err := srv.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Metrics server stopped", slog.String("error", err.Error()))
+		}
+	}()
+	log.Info("Metrics served", slog.String("address", cfg.Address), slog.String("path", metricsPath))
+	return srv
+}
+
+// deadLettered reads from the metrics of the running server how many requests of every queue
+// it has dead-lettered since it started
+func deadLettered(cfg config.MetricsConfig) (map[string]int64, error) {
+	address := cfg.Address
+	if strings.HasPrefix(address, ":") {
+		address = "localhost" + address
+	}
+	client := http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get("http://" + address + metricsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("metrics server answered %s", resp.Status)
+	}
+
+	var vars struct {
+		RPC map[string]struct {
+			DeadLettered int64 `json:"dead_lettered"`
+		} `json:"rpc"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&vars)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(vars.RPC))
+	for queue, stats := range vars.RPC {
+		counts[queue] = stats.DeadLettered
+	}
+	return counts, nil
+}
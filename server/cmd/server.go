@@ -2,15 +2,21 @@ package main
 
 import (
 	"battle-ship_server/internal/config"
+	"battle-ship_server/internal/port/gateway"
+	"battle-ship_server/internal/port/grpcapi"
 	"battle-ship_server/internal/port/rabbitmq"
 	"battle-ship_server/internal/service/auth"
 	"battle-ship_server/internal/service/game"
+	"battle-ship_server/internal/service/game/rating"
 	"battle-ship_server/internal/storage/postgres"
+	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 func main() {
@@ -18,21 +24,56 @@ func main() {
 
 	log := setupLogger(cfg.Env)
 
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:], cfg, log))
+	}
+
 	log.Info("Starting server")
 
-	postgresUrl := fmt.Sprintf("postgres://%s:%s@%s:%s/%s", cfg.Postgres.User, cfg.Postgres.Password, cfg.Postgres.Host, cfg.Postgres.Port, cfg.Postgres.DBName)
-	storage, err := postgres.New(postgresUrl)
+	storage, err := setupStorage(cfg)
 	if err != nil {
 		panic(err)
 	}
+	log.Info("Storage opened", slog.String("driver", cfg.Storage.Driver))
 
-	auth := auth.New(storage, log)
-	game := game.New(storage, log)
+	auth := auth.New(storage, log, cfg.Auth.TokenSecret, cfg.Auth.TokenTTL)
+	game := game.New(storage, log, setupRating(cfg.Rating), setupTimeouts(cfg.Game), setupMatchmaking(cfg.Matchmaking))
 
-	rmqUrl := fmt.Sprintf("amqp://%s:%s@%s:%s/", cfg.RabbitMQ.User, cfg.RabbitMQ.Password, cfg.RabbitMQ.Host, cfg.RabbitMQ.Port)
-	rmq := rabbitmq.New(rmqUrl, log, auth, game)
+	// every port delivers the events of the games to its own players
+	names := []string{"rabbitmq"}
+	if cfg.Gateway.Enabled {
+		names = append(names, "gateway")
+	}
+	if cfg.GRPC.Enabled {
+		names = append(names, "grpc")
+	}
+	ports := shareEvents(game, log, names...)
+
+	rmq := rabbitmq.New(rabbitmqURL(cfg.RabbitMQ), log, setupConsumers(cfg.RabbitMQ), auth, ports[0])
+	ports = ports[1:]
+	var gw *gateway.Gateway
+	if cfg.Gateway.Enabled {
+		gw = gateway.New(setupGateway(cfg.Gateway), log, auth, ports[0])
+		ports = ports[1:]
+	}
+	var grpcSrv *grpcapi.Server
+	if cfg.GRPC.Enabled {
+		grpcSrv = grpcapi.New(setupGRPC(cfg.GRPC), log, auth, ports[0])
+	}
 
 	rmq.Run()
+	var metrics *http.Server
+	if cfg.Metrics.Enabled {
+		metrics = serveMetrics(cfg.Metrics, log)
+	}
+	if gw != nil {
+		gw.Run()
+	}
+	if grpcSrv != nil {
+		if err := grpcSrv.Run(); err != nil {
+			panic(err)
+		}
+	}
 
 	log.Info("Server started")
 
@@ -41,18 +82,108 @@ func main() {
 
 	<-stop // wait for SIGTERM or SIGINT signal
 
-	err = rmq.Close()
-	if err != nil {
-		log.Error("Failed to close rabbitmq connection: %v", err)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	if gw != nil {
+		err = gw.Close(ctx)
+		if err != nil {
+			log.Error("Failed to stop the gateway", slog.String("error", err.Error()))
+		}
+	}
+	if grpcSrv != nil {
+		grpcSrv.Close(ctx)
+	}
+	if metrics != nil {
+		err = metrics.Shutdown(ctx)
+		if err != nil {
+			log.Error("Failed to stop the metrics server", slog.String("error", err.Error()))
+		}
 	}
-	err = storage.Close()
+	cancel()
+	game.Close()
+	err = rmq.Close()
 	if err != nil {
-		log.Error("Failed to close postgres connection: %v", err)
+		log.Error("Failed to close rabbitmq connection", slog.String("error", err.Error()))
 	}
+	storage.Close()
 	log.Info("Gracefully stopped")
 
 }
 
+// shutdownTimeout is how long the requests in flight of the gateway and the gRPC API may take to complete on stop
+const shutdownTimeout = 10 * time.Second
+
+func setupGateway(cfg config.GatewayConfig) gateway.Config {
+	return gateway.Config{
+		Address:        cfg.Address,
+		WaitTimeout:    cfg.WaitTimeout,
+		AllowedOrigins: cfg.AllowedOrigins,
+	}
+}
+
+func setupGRPC(cfg config.GRPCConfig) grpcapi.Config {
+	return grpcapi.Config{
+		Address:     cfg.Address,
+		WaitTimeout: cfg.WaitTimeout,
+	}
+}
+
+func rabbitmqURL(cfg config.RabbitMQConfig) string {
+	return fmt.Sprintf("amqp://%s:%s@%s:%s/", cfg.User, cfg.Password, cfg.Host, cfg.Port)
+}
+
+func postgresURL(cfg config.PostgresConfig) string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName)
+}
+
+func setupPool(cfg config.PostgresConfig) postgres.PoolConfig {
+	return postgres.PoolConfig{
+		MaxConns:        cfg.MaxConns,
+		MinConns:        cfg.MinConns,
+		MaxConnLifetime: cfg.MaxConnLifetime,
+		MaxConnIdleTime: cfg.MaxConnIdleTime,
+		ConnectTimeout:  cfg.ConnectTimeout,
+		QueryTimeout:    cfg.QueryTimeout,
+	}
+}
+
+func setupConsumers(cfg config.RabbitMQConfig) rabbitmq.Consumers {
+	consumers := rabbitmq.Consumers{
+		Default:    rabbitmq.Consumer{Workers: cfg.Workers, Prefetch: cfg.Prefetch},
+		Endpoints:  make(map[string]rabbitmq.Consumer, len(cfg.Endpoints)),
+		MaxRetries: cfg.MaxRetries,
+	}
+	for queue, e := range cfg.Endpoints {
+		consumers.Endpoints[queue] = rabbitmq.Consumer{Workers: e.Workers, Prefetch: e.Prefetch}
+	}
+	return consumers
+}
+
+func setupRating(cfg config.RatingConfig) rating.Calculator {
+	switch cfg.System {
+	case "glicko2":
+		return rating.NewGlicko2(cfg.Initial, cfg.Glicko2.Deviation, cfg.Glicko2.Volatility, cfg.Glicko2.Tau)
+	default:
+		return rating.NewElo(cfg.Initial, cfg.Elo.KFactor)
+	}
+}
+
+func setupTimeouts(cfg config.GameConfig) game.Timeouts {
+	return game.Timeouts{
+		Heartbeat: cfg.HeartbeatTimeout,
+		Placement: cfg.PlacementTimeout,
+		Turn:      cfg.TurnTimeout,
+	}
+}
+
+func setupMatchmaking(cfg config.MatchmakingConfig) game.Matchmaking {
+	return game.Matchmaking{
+		InitialGap: cfg.InitialGap,
+		GapGrowth:  cfg.GapGrowth,
+		MaxGap:     cfg.MaxGap,
+		Interval:   cfg.Interval,
+	}
+}
+
 func setupLogger(env string) *slog.Logger {
 	var log *slog.Logger
 	switch env {
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"battle-ship_server/internal/config"
+	"battle-ship_server/internal/service/auth"
+	"battle-ship_server/internal/service/game"
+	"battle-ship_server/internal/storage/memory"
+	"battle-ship_server/internal/storage/migrate"
+	"battle-ship_server/internal/storage/postgres"
+	"battle-ship_server/internal/storage/sqlite"
+	"fmt"
+)
+
+// Storage is implemented by every storage driver
+type Storage interface {
+	auth.UserStorage
+	game.StatStorage
+	Close()
+}
+
+// setupStorage opens the storage selected by the storage.driver config key
+func setupStorage(cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Driver {
+	case "memory":
+		return memory.New(), nil
+	case "sqlite":
+		return sqlite.New(cfg.Storage.SQLite.Path)
+	case "postgres":
+		return postgres.New(postgresURL(cfg.Postgres), setupPool(cfg.Postgres))
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
+
+// migrator is implemented by the migrators of the storage drivers with a database schema
+type migrator interface {
+	Latest() int
+	Version() (int, error)
+	Status() ([]migrate.Migration, error)
+	Up() (int, error)
+	Down(steps int) (int, error)
+	Close()
+}
+
+// openMigrator opens the database of the storage selected by the storage.driver config key to migrate its schema
+func openMigrator(cfg *config.Config) (migrator, error) {
+	switch cfg.Storage.Driver {
+	case "sqlite":
+		m, err := sqlite.NewMigrator(cfg.Storage.SQLite.Path)
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+	case "postgres":
+		m, err := postgres.NewMigrator(postgresURL(cfg.Postgres))
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("the %s storage has no migrations", cfg.Storage.Driver)
+	}
+}
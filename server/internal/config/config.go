@@ -1,14 +1,24 @@
 package config
 
 import (
+	"time"
+
 	"github.com/go-playground/validator"
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type Config struct {
-	Env      string         `yaml:"env" validate:"required,oneof=local dev prod"`
-	RabbitMQ RabbitMQConfig `yaml:"rabbitmq" validate:"required"`
-	Postgres PostgresConfig `yaml:"postgres" validate:"required"`
+	Env         string            `yaml:"env" validate:"required,oneof=local dev prod"`
+	RabbitMQ    RabbitMQConfig    `yaml:"rabbitmq" validate:"required"`
+	Storage     StorageConfig     `yaml:"storage"`
+	Postgres    PostgresConfig    `yaml:"postgres" validate:"-"` // validated only for the postgres storage driver
+	Auth        AuthConfig        `yaml:"auth" validate:"required"`
+	Rating      RatingConfig      `yaml:"rating"`
+	Game        GameConfig        `yaml:"game"`
+	Matchmaking MatchmakingConfig `yaml:"matchmaking"`
+	Gateway     GatewayConfig     `yaml:"gateway"`
+	GRPC        GRPCConfig        `yaml:"grpc"`
+	Metrics     MetricsConfig     `yaml:"metrics"`
 }
 
 type RabbitMQConfig struct {
@@ -16,6 +26,32 @@ type RabbitMQConfig struct {
 	Port     string `yaml:"port" validate:"required,numeric,gte=0,lte=65535"`
 	User     string `yaml:"user" validate:"required"`
 	Password string `yaml:"password" validate:"required"`
+
+	// Workers handle the requests of a queue at once, the broker hands over up to Prefetch requests not yet acknowledged
+	Workers   int                       `yaml:"workers" env-default:"4" validate:"gt=0"`
+	Prefetch  int                       `yaml:"prefetch" env-default:"8" validate:"gtefield=Workers"`
+	Endpoints map[string]EndpointConfig `yaml:"endpoints" validate:"dive"` // queue name -> settings of the queue
+
+	// MaxRetries is how many times a request failed by a transient error is tried again before it is dead-lettered
+	MaxRetries int `yaml:"max_retries" env-default:"3" validate:"gte=0"`
+}
+
+// EndpointConfig overrides the consumer settings of a request queue, zero keeps the default
+type EndpointConfig struct {
+	Workers  int `yaml:"workers" validate:"gte=0"`
+	Prefetch int `yaml:"prefetch" validate:"gte=0"`
+}
+
+// StorageConfig selects where the users, the statistics and the matches are kept.
+// The memory driver needs no database, everything is lost when the server stops.
+// The sqlite driver keeps everything in a single file, for the deployments on one host.
+type StorageConfig struct {
+	Driver string       `yaml:"driver" env:"STORAGE_DRIVER" env-default:"postgres" validate:"oneof=postgres sqlite memory"`
+	SQLite SQLiteConfig `yaml:"sqlite"`
+}
+
+type SQLiteConfig struct {
+	Path string `yaml:"path" env:"SQLITE_PATH" env-default:"battleship.db" validate:"required"`
 }
 
 type PostgresConfig struct {
@@ -24,6 +60,75 @@ type PostgresConfig struct {
 	User     string `yaml:"user" validate:"required"`
 	Password string `yaml:"password" validate:"required"`
 	DBName   string `yaml:"dbname" validate:"required"`
+
+	MaxConns        int32         `yaml:"max_conns" env-default:"10" validate:"gt=0"`
+	MinConns        int32         `yaml:"min_conns" env-default:"0" validate:"gte=0,ltefield=MaxConns"`
+	MaxConnLifetime time.Duration `yaml:"max_conn_lifetime" env-default:"1h" validate:"gt=0"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time" env-default:"30m" validate:"gt=0"`
+	ConnectTimeout  time.Duration `yaml:"connect_timeout" env-default:"5s" validate:"gt=0"`
+	QueryTimeout    time.Duration `yaml:"query_timeout" env-default:"5s" validate:"gt=0"` // every storage call must complete within it
+}
+
+type AuthConfig struct {
+	TokenSecret string        `yaml:"token_secret" env:"AUTH_TOKEN_SECRET" validate:"required,min=32"`
+	TokenTTL    time.Duration `yaml:"token_ttl" env-default:"24h" validate:"gt=0"`
+}
+
+type RatingConfig struct {
+	System  string        `yaml:"system" env-default:"elo" validate:"oneof=elo glicko2"`
+	Initial float64       `yaml:"initial" env-default:"1500"`
+	Elo     EloConfig     `yaml:"elo"`
+	Glicko2 Glicko2Config `yaml:"glicko2"`
+}
+
+type EloConfig struct {
+	KFactor float64 `yaml:"k_factor" env-default:"32" validate:"gt=0"`
+}
+
+type Glicko2Config struct {
+	Deviation  float64 `yaml:"deviation" env-default:"350" validate:"gt=0"`
+	Volatility float64 `yaml:"volatility" env-default:"0.06" validate:"gt=0"`
+	Tau        float64 `yaml:"tau" env-default:"0.5" validate:"gt=0"`
+}
+
+// GameConfig sets when a running game is forfeited by a player.
+// HeartbeatTimeout is also the grace window for a disconnected player to log back in and resume the game.
+type GameConfig struct {
+	HeartbeatTimeout time.Duration `yaml:"heartbeat_timeout" env-default:"60s" validate:"gt=0"`
+	PlacementTimeout time.Duration `yaml:"placement_timeout" env-default:"5m" validate:"gt=0"`
+	TurnTimeout      time.Duration `yaml:"turn_timeout" env-default:"2m" validate:"gt=0"`
+}
+
+// MatchmakingConfig sets how quick match opponents are chosen.
+// The acceptable rating gap starts at InitialGap and grows by GapGrowth every second of waiting up to MaxGap.
+type MatchmakingConfig struct {
+	InitialGap float64       `yaml:"initial_gap" env-default:"100" validate:"gte=0"`
+	GapGrowth  float64       `yaml:"gap_growth" env-default:"10" validate:"gte=0"`
+	MaxGap     float64       `yaml:"max_gap" env-default:"800" validate:"gte=0"` // 0 means no limit
+	Interval   time.Duration `yaml:"interval" env-default:"1s" validate:"gt=0"`  // how often the queue is checked
+}
+
+// GatewayConfig enables the HTTP and WebSocket gateway for the clients which can't speak AMQP.
+// It serves the same requests as the AMQP port, the players of both can play together.
+type GatewayConfig struct {
+	Enabled        bool          `yaml:"enabled" env:"GATEWAY_ENABLED" env-default:"false"`
+	Address        string        `yaml:"address" env:"GATEWAY_ADDRESS" env-default:":8080" validate:"required"`
+	WaitTimeout    time.Duration `yaml:"wait_timeout" env-default:"2m" validate:"gt=0"` // how long the creator of a game and the quick match wait for the opponent
+	AllowedOrigins []string      `yaml:"allowed_origins"`                               // origins of the web clients, the gateway's own only if empty
+}
+
+// GRPCConfig enables the gRPC API for the tools and the bots, see api/battleship/v1
+type GRPCConfig struct {
+	Enabled     bool          `yaml:"enabled" env:"GRPC_ENABLED" env-default:"false"`
+	Address     string        `yaml:"address" env:"GRPC_ADDRESS" env-default:":9090" validate:"required"`
+	WaitTimeout time.Duration `yaml:"wait_timeout" env-default:"2m" validate:"gt=0"` // how long the creator of a game waits for the opponent
+}
+
+// MetricsConfig enables the expvar metrics at /debug/vars, e.g. the counters of the AMQP endpoints.
+// The address should not be reachable by the players.
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled" env:"METRICS_ENABLED" env-default:"false"`
+	Address string `yaml:"address" env:"METRICS_ADDRESS" env-default:"localhost:9100" validate:"required"`
 }
 
 func MustLoad(configPath string) *Config {
@@ -40,10 +145,18 @@ func MustLoad(configPath string) *Config {
 	// cfg.Postgres.Password = os.Getenv("POSTGRES_PASSWORD")
 	// cfg.RabbitMQ.Password = os.Getenv("RABBITMQ_PASSWORD")
 
-	err = validator.New().Struct(cfg)
+	v := validator.New()
+	err = v.Struct(cfg)
 	if err != nil {
 		panic(err)
 	}
+	// the validator has no required_if, the other drivers need no postgres section
+	if cfg.Storage.Driver == "postgres" {
+		err = v.Struct(cfg.Postgres)
+		if err != nil {
+			panic(err)
+		}
+	}
 
 	return &cfg
 }
@@ -0,0 +1,90 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (req loginRequest) validate() error {
+	if req.Username == "" || req.Password == "" {
+		return errors.New("empty username or password")
+	}
+	return nil
+}
+
+type loginResponse struct {
+	Token string `json:"token"`
+}
+
+type registerRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (req registerRequest) validate() error {
+	if req.Username == "" || req.Password == "" {
+		return errors.New("empty username or password")
+	}
+	return nil
+}
+
+type registerResponse struct {
+	Token string `json:"token"`
+}
+
+// the session to end is taken from the Authorization header
+type logoutRequest struct{}
+
+type logoutResponse struct{}
+
+const ( // the names of the AMQP queues of the same requests
+	authLogin    = "auth.login"
+	authRegister = "auth.register"
+	authLogout   = "auth.logout"
+)
+
+type authService interface {
+	Login(ctx context.Context, username, password string) (token string, err error)
+	Register(ctx context.Context, username, password string) (token string, err error)
+	Logout(token string) (login string, err error)
+	ValidateToken(token string) (login string, err error)
+}
+
+func (g *Gateway) login(r *request, req loginRequest) (loginResponse, error) {
+	token, err := g.auth.Login(r.ctx, req.Username, req.Password)
+	if err != nil {
+		return loginResponse{}, err
+	}
+	return loginResponse{Token: token}, nil
+}
+
+func (g *Gateway) register(r *request, req registerRequest) (registerResponse, error) {
+	token, err := g.auth.Register(r.ctx, req.Username, req.Password)
+	if err != nil {
+		return registerResponse{}, err
+	}
+	return registerResponse{Token: token}, nil
+}
+
+// logout revokes the session of the Authorization header itself, so the route is served without authorization
+func (g *Gateway) logout(r *request, _ logoutRequest) (logoutResponse, error) {
+	login, err := g.auth.Logout(r.token)
+	if err != nil {
+		return logoutResponse{}, ErrUnauthorized
+	}
+
+	// the user can't come back to the games without a session
+	err = g.game.LeaveGames(r.ctx, login)
+	if err != nil {
+		r.log.Error("Failed to leave games", slog.String("login", login), slog.String("error", err.Error()))
+	}
+	g.hub.dropPlayer(login)
+
+	return logoutResponse{}, nil
+}
@@ -0,0 +1,192 @@
+package gateway
+
+import (
+	"battle-ship_server/internal/service/game"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+type battleService interface {
+	PlaceFleet(userName string, fleet [][]game.Point) error
+	Attack(ctx context.Context, userName string, x, y int) error
+	Heartbeat(userName string) error
+	Chat(userName, text string) error
+	Resume(userName string) (game.GameState, error)
+	Events() <-chan game.Event
+}
+
+// battleErrors are the errors of the moves shown to the player
+var battleErrors = route{path: gameBattle, errors: []error{
+	game.ErrGameNotFound,
+	game.ErrNotYourTurn,
+	game.ErrFleetPlaced,
+	game.ErrNotStarted,
+	game.ErrBadFleet,
+	game.ErrBadShot,
+	game.ErrAlreadyShot,
+	game.ErrChatEmpty,
+	game.ErrChatTooLong,
+	game.ErrChatTooFast,
+}}
+
+// battle serves the WebSocket of a player. The player sends the moves as battleRequest
+// and gets the events of the running game as battleMessage. The moves are not answered,
+// the results come with the events, only the errors are sent back with the type of the request.
+// The pongs of the WebSocket keep the player present in the game, as the heartbeats do.
+func (g *Gateway) battle(w http.ResponseWriter, hr *http.Request) {
+	const op = "Gateway.battle"
+
+	login, err := g.authorize(token(hr))
+	if err != nil {
+		writeJSON(w, http.StatusUnauthorized, errorResponse{Err: err.Error()})
+		return
+	}
+
+	log := g.log.With(
+		slog.String("op", op),
+		slog.String("login", login),
+	)
+
+	ws, err := g.upgrader.Upgrade(w, hr, nil)
+	if err != nil {
+		log.Info("Failed to upgrade to a WebSocket", slog.String("error", err.Error()))
+		return // the upgrader has answered
+	}
+	c := newConn(ws, func() {
+		_ = g.game.Heartbeat(login) // no running game is not an error
+	})
+	g.hub.addPlayer(login, c)
+	log.Debug("Player connected")
+	defer func() {
+		g.hub.removePlayer(login, c)
+		c.close()
+		log.Debug("Player disconnected")
+	}()
+
+	for {
+		msg, err := c.read()
+		if err != nil {
+			return
+		}
+
+		var req battleRequest
+		if err := json.Unmarshal(msg, &req); err != nil {
+			c.push(battleMessage{Type: req.Type, Err: ErrBadRequest.Error()})
+			continue
+		}
+		if err := g.move(login, req); err != nil {
+			shown := battleErrors.expose(err)
+			if shown == ErrInternal {
+				log.Error("Move failed", slog.String("error", err.Error()))
+			} else {
+				log.Info("Move rejected", slog.String("error", err.Error()))
+			}
+			c.push(battleMessage{Type: req.Type, Err: shown.Error()})
+		}
+	}
+}
+
+// move passes the move of the player to the game service
+func (g *Gateway) move(login string, req battleRequest) error {
+	switch req.Type {
+	case heartbeat:
+		_ = g.game.Heartbeat(login) // a late heartbeat of a finished game is not an error
+		return nil
+	case ready:
+		return g.game.PlaceFleet(login, toFleet(req.Ships))
+	case attack:
+		return g.game.Attack(g.ctx, login, req.X, req.Y)
+	case chat:
+		return g.game.Chat(login, req.Text)
+	}
+	return ErrBadRequest
+}
+
+// GameEvents delivers the events of the game service to the WebSockets of the players and the spectators
+// until the channel of the events is closed. The start of a game wakes up the requests waiting for the opponent.
+func (g *Gateway) GameEvents() {
+	for e := range g.game.Events() {
+		if e.To == "" {
+			g.hub.toSpectators(e.GameID, toSpectatorMessage(e))
+			continue
+		}
+		if e.Type == game.EventStarted {
+			g.waiters.wake(e)
+			continue
+		}
+
+		var msg battleMessage
+		switch e.Type {
+		case game.EventReady:
+			msg = battleMessage{Type: ready, First: e.First}
+		case game.EventShot:
+			msg = battleMessage{Type: result, Attacker: e.Attacker, X: e.X, Y: e.Y, Hit: e.Hit, Destroy: e.Destroy}
+		case game.EventEnd:
+			msg = battleMessage{Type: end, Attacker: e.Attacker, X: e.X, Y: e.Y, Hit: e.Hit, Destroy: e.Destroy, Winner: e.Winner, Reason: string(e.Reason)}
+		case game.EventChat:
+			msg = battleMessage{Type: chat, From: e.From, Text: e.Text}
+		}
+		// the players of the AMQP port get the event there
+		g.hub.toPlayer(e.To, msg)
+	}
+}
+
+// resume lets a player who reconnected continue the running game or give it up
+func (g *Gateway) resume(r *request, req resumeRequest) (resumeResponse, error) {
+	if req.Forfeit {
+		return resumeResponse{}, g.game.LeaveGames(r.ctx, r.user)
+	}
+
+	state, err := g.game.Resume(r.user)
+	if errors.Is(err, game.ErrGameNotFound) {
+		return resumeResponse{}, nil // no opponent, nothing to resume
+	} else if err != nil {
+		return resumeResponse{}, err
+	}
+
+	r.log.Info("game state sent")
+	return resumeResponse{
+		Opponent:      state.Opponent,
+		FleetPlaced:   state.FleetPlaced,
+		Started:       state.Started,
+		MyTurn:        state.MyTurn,
+		Fleet:         toPoints(state.Fleet),
+		MyShots:       shotInfos(state.MyShots),
+		OpponentShots: shotInfos(state.OpponentShots),
+	}, nil
+}
+
+func toFleet(ships [][]point) [][]game.Point {
+	fleet := make([][]game.Point, 0, len(ships))
+	for _, ship := range ships {
+		cells := make([]game.Point, 0, len(ship))
+		for _, p := range ship {
+			cells = append(cells, game.Point{X: p.X, Y: p.Y})
+		}
+		fleet = append(fleet, cells)
+	}
+	return fleet
+}
+
+func toPoints(fleet [][]game.Point) [][]point {
+	ships := make([][]point, 0, len(fleet))
+	for _, ship := range fleet {
+		cells := make([]point, 0, len(ship))
+		for _, p := range ship {
+			cells = append(cells, point{X: p.X, Y: p.Y})
+		}
+		ships = append(ships, cells)
+	}
+	return ships
+}
+
+func shotInfos(shots []game.Shot) []shotInfo {
+	infos := make([]shotInfo, 0, len(shots))
+	for _, s := range shots {
+		infos = append(infos, shotInfo{X: s.X, Y: s.Y, Hit: s.Hit, Destroy: s.Destroy})
+	}
+	return infos
+}
@@ -0,0 +1,277 @@
+package gateway
+
+import (
+	"battle-ship_server/internal/service/game"
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"slices"
+	"sync"
+)
+
+type gameService interface {
+	CreateGame(userName string) error
+	DelGame(userName string) (user2 string, err error)
+	GetAvailableGames() (games []string, err error)
+	JoinGame(creatorUserName, joiningUserName string) error
+	SaveGameResult(submitter, winner, loser string) error
+	GetUserStat(ctx context.Context, userName string) (game.Statistics, error)
+	LeaveGames(ctx context.Context, userName string) error
+	GetHistory(ctx context.Context, userName string, page, pageSize int) (matches []game.Match, total int, err error)
+	GetLeaderboard(ctx context.Context, userName string, page, pageSize int) (entries []game.LeaderboardEntry, total int, userRank int, err error)
+	GetReplay(ctx context.Context, matchID int64) (game.Match, error)
+	QuickMatch(ctx context.Context, userName string) error
+	CancelQuickMatch(userName string) error
+	battleService
+	spectateService
+}
+
+// waiters are the requests of the users waiting for an opponent, GameEvents wakes them up on EventStarted
+type waiters struct {
+	mu    sync.Mutex
+	chans map[string][]chan game.Event // login -> the requests of the user
+}
+
+func newWaiters() *waiters {
+	return &waiters{chans: make(map[string][]chan game.Event)}
+}
+
+// add returns the channel the start of the next game of the user is sent to
+func (w *waiters) add(login string) chan game.Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch := make(chan game.Event, 1)
+	w.chans[login] = append(w.chans[login], ch)
+	return ch
+}
+
+// remove forgets the request, it may be woken up already
+func (w *waiters) remove(login string, ch chan game.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	chans := slices.DeleteFunc(w.chans[login], func(c chan game.Event) bool { return c == ch })
+	if len(chans) == 0 {
+		delete(w.chans, login)
+		return
+	}
+	w.chans[login] = chans
+}
+
+// wake sends the start of the game to the requests of the player, every channel gets a single event
+func (w *waiters) wake(e game.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, ch := range w.chans[e.To] {
+		ch <- e
+	}
+	delete(w.chans, e.To)
+}
+
+// createGame answers once another user joins the game. The game is deleted if nobody joins
+// within the wait timeout or the client goes away.
+func (g *Gateway) createGame(r *request, _ gameCreateRequest) (gameCreateResponse, error) {
+	started := g.waiters.add(r.user)
+	defer g.waiters.remove(r.user, started)
+
+	err := g.game.CreateGame(r.user)
+	if err != nil {
+		return gameCreateResponse{}, err
+	}
+	r.log.Info("Game created successfully")
+
+	e, err := g.waitForOpponent(r.ctx, started)
+	if err != nil {
+		if _, err := g.game.DelGame(r.user); err != nil {
+			r.log.Error("Failed to delete the game", slog.String("error", err.Error()))
+		}
+		return gameCreateResponse{}, err
+	}
+	return gameCreateResponse{User2: e.Opponent}, nil
+}
+
+// joinGame answers the joining user at once, the creator is told about the start on the port it waits on
+func (g *Gateway) joinGame(r *request, req gameJoinRequest) (gameJoinResponse, error) {
+	err := g.game.JoinGame(req.CreatorUserName, r.user)
+	if err != nil {
+		return gameJoinResponse{}, err
+	}
+	r.log.Info("Game joined", slog.String("creator", req.CreatorUserName))
+	return gameJoinResponse{}, nil
+}
+
+// quickMatch answers once the opponent is found, or at once to a cancel request.
+// The user is taken out of the queue if no opponent is found within the wait timeout or the client goes away.
+func (g *Gateway) quickMatch(r *request, req quickMatchRequest) (quickMatchResponse, error) {
+	if req.Cancel {
+		err := g.game.CancelQuickMatch(r.user)
+		if err != nil {
+			return quickMatchResponse{}, err
+		}
+		r.log.Info("quick match cancelled")
+		return quickMatchResponse{}, nil
+	}
+
+	// the opponent may be found before QuickMatch returns
+	started := g.waiters.add(r.user)
+	defer g.waiters.remove(r.user, started)
+
+	err := g.game.QuickMatch(r.ctx, r.user)
+	if err != nil {
+		return quickMatchResponse{}, err
+	}
+
+	e, err := g.waitForOpponent(r.ctx, started)
+	if err != nil {
+		if err := g.game.CancelQuickMatch(r.user); err != nil && !errors.Is(err, game.ErrNotQueued) {
+			r.log.Error("Failed to cancel the quick match", slog.String("error", err.Error()))
+		}
+		return quickMatchResponse{}, err
+	}
+	return quickMatchResponse{Opponent: e.Opponent, OpponentRating: int(math.Round(e.OpponentRating))}, nil
+}
+
+// waitForOpponent waits for the start of the game of the user.
+// It gives up with ErrTimeout after the wait timeout, or with the error of ctx once the client goes away.
+func (g *Gateway) waitForOpponent(ctx context.Context, started <-chan game.Event) (game.Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.cfg.WaitTimeout)
+	defer cancel()
+
+	select {
+	case e := <-started:
+		return e, nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return game.Event{}, ErrTimeout
+		}
+		return game.Event{}, ctx.Err()
+	}
+}
+
+func (g *Gateway) getAvailableGames(r *request, _ getAvailableGamesRequest) (getAvailableGamesResponse, error) {
+	games, err := g.game.GetAvailableGames()
+	if err != nil {
+		return getAvailableGamesResponse{}, err
+	}
+
+	r.log.Debug("Sent games", slog.Any("games", games))
+	return getAvailableGamesResponse{Games: games}, nil
+}
+
+func (g *Gateway) gameResult(r *request, req gameResultRequest) (gameResultResponse, error) {
+	return gameResultResponse{}, g.game.SaveGameResult(r.user, req.Winner, req.Loser)
+}
+
+func (g *Gateway) getUserStat(r *request, req getStatRequest) (getStatResponse, error) {
+	stat, err := g.game.GetUserStat(r.ctx, req.UserName)
+	if err != nil {
+		return getStatResponse{}, err
+	}
+
+	r.log.Info("user stat sent", slog.String("user_name", req.UserName))
+	return getStatResponse{
+		Rating: int(math.Round(stat.Rating.Value)),
+		Wins:   stat.Wins,
+		Losses: stat.Losses,
+	}, nil
+}
+
+func (g *Gateway) delGame(r *request, _ gameDelRequest) (gameDelResponse, error) {
+	_, err := g.game.DelGame(r.user)
+	if err != nil {
+		return gameDelResponse{}, err
+	}
+
+	r.log.Info("game deleted")
+	return gameDelResponse{}, nil
+}
+
+func (g *Gateway) getHistory(r *request, req getHistoryRequest) (getHistoryResponse, error) {
+	userName := r.user
+	if req.UserName != "" {
+		userName = req.UserName
+	}
+
+	matches, total, err := g.game.GetHistory(r.ctx, userName, req.Page, req.PageSize)
+	if err != nil {
+		return getHistoryResponse{}, err
+	}
+
+	resp := getHistoryResponse{Matches: make([]matchInfo, 0, len(matches)), Total: total}
+	for _, m := range matches {
+		resp.Matches = append(resp.Matches, toMatchInfo(m))
+	}
+	r.log.Info("history sent", slog.String("user_name", userName))
+	return resp, nil
+}
+
+func (g *Gateway) getLeaderboard(r *request, req getLeaderboardRequest) (getLeaderboardResponse, error) {
+	entries, total, rank, err := g.game.GetLeaderboard(r.ctx, r.user, req.Page, req.PageSize)
+	if err != nil {
+		return getLeaderboardResponse{}, err
+	}
+
+	resp := getLeaderboardResponse{Entries: make([]leaderboardEntry, 0, len(entries)), Total: total, MyRank: rank}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, leaderboardEntry{
+			Rank:     e.Rank,
+			UserName: e.Login,
+			Rating:   int(math.Round(e.Stat.Rating.Value)),
+			Wins:     e.Stat.Wins,
+			Losses:   e.Stat.Losses,
+		})
+	}
+	r.log.Info("leaderboard sent")
+	return resp, nil
+}
+
+// getReplay sends a finished game with the ordered log of its events
+func (g *Gateway) getReplay(r *request, req getReplayRequest) (getReplayResponse, error) {
+	match, err := g.game.GetReplay(r.ctx, req.MatchID)
+	if err != nil {
+		return getReplayResponse{}, err
+	}
+
+	resp := getReplayResponse{
+		Match:  toMatchInfo(match),
+		Events: make([]replayEvent, 0, len(match.Events)),
+	}
+	for _, e := range match.Events {
+		var fleet [][]point
+		if len(e.Fleet) > 0 {
+			fleet = toPoints(e.Fleet)
+		}
+		resp.Events = append(resp.Events, replayEvent{
+			Seq:     e.Seq,
+			Type:    string(e.Type),
+			Player:  e.Player,
+			X:       e.X,
+			Y:       e.Y,
+			Hit:     e.Hit,
+			Destroy: e.Destroy,
+			Fleet:   fleet,
+			Reason:  string(e.Reason),
+			At:      e.At,
+		})
+	}
+	r.log.Info("replay sent", slog.Int64("match_id", req.MatchID))
+	return resp, nil
+}
+
+func toMatchInfo(m game.Match) matchInfo {
+	return matchInfo{
+		ID:                m.ID,
+		Winner:            m.Winner,
+		Loser:             m.Loser,
+		StartedAt:         m.StartedAt,
+		EndedAt:           m.EndedAt,
+		DurationSec:       int(m.Duration().Seconds()),
+		Shots:             m.Shots,
+		WinnerRatingDelta: m.WinnerRatingDelta,
+		LoserRatingDelta:  m.LoserRatingDelta,
+		EndReason:         string(m.EndReason),
+	}
+}
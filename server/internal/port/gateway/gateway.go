@@ -0,0 +1,304 @@
+// Package gateway serves the clients which can't speak AMQP, e.g. the web and the mobile ones.
+// The requests are POSTed as JSON to the paths named after the queues of the AMQP port,
+// game.get_history is served at /api/game/get_history, and the battle and the spectated games go over WebSockets.
+package gateway
+
+import (
+	"battle-ship_server/internal/service/auth"
+	"battle-ship_server/internal/service/game"
+	"battle-ship_server/internal/storage"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrInternal     = errors.New("internal error")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrTimeout      = errors.New("timeout")
+)
+
+// maxBodySize limits the bodies of the requests and the WebSocket messages, a fleet is the largest of them
+const maxBodySize = 64 << 10
+
+type Config struct {
+	Address string
+	// WaitTimeout is how long the creator of a game and the quick match wait for the opponent
+	WaitTimeout time.Duration
+	// AllowedOrigins may open the WebSockets from the browsers, only the gateway's own origin if empty
+	AllowedOrigins []string
+}
+
+type Gateway struct {
+	cfg      Config
+	srv      *http.Server
+	upgrader websocket.Upgrader
+	hub      *hub
+	waiters  *waiters
+	log      *slog.Logger
+
+	// ctx is passed to the services for the moves made over the WebSockets, it is cancelled on Close
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	auth authService
+	game gameService
+}
+
+func New(cfg Config, log *slog.Logger, auth authService, game gameService) *Gateway {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &Gateway{
+		cfg:     cfg,
+		hub:     newHub(),
+		waiters: newWaiters(),
+		log:     log,
+		ctx:     ctx,
+		cancel:  cancel,
+		auth:    auth,
+		game:    game,
+	}
+	g.upgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+	}
+	if len(cfg.AllowedOrigins) > 0 {
+		g.upgrader.CheckOrigin = func(r *http.Request) bool {
+			return slices.Contains(cfg.AllowedOrigins, r.Header.Get("Origin"))
+		}
+	}
+
+	mux := http.NewServeMux()
+	g.routes(mux)
+	g.srv = &http.Server{
+		Addr:              cfg.Address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return g
+}
+
+// Run starts serving the requests and delivering the events of the game service to the WebSockets
+func (g *Gateway) Run() {
+	const op = "Gateway.Run"
+
+	log := g.log.With(
+		slog.String("op", op),
+		slog.String("address", g.cfg.Address),
+	)
+
+	go g.GameEvents()
+	go func() {
+		err := g.srv.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Gateway stopped", slog.String("error", err.Error()))
+		}
+	}()
+	log.Info("Gateway started")
+}
+
+func (g *Gateway) routes(mux *http.ServeMux) {
+	handle(mux, g, route{path: authLogin, public: true, errors: []error{auth.ErrWrongPass, storage.ErrUserNotFound}}, g.login)
+	handle(mux, g, route{path: authRegister, public: true, errors: []error{storage.ErrUserExists}}, g.register)
+	handle(mux, g, route{path: authLogout, public: true}, g.logout)
+
+	handle(mux, g, route{path: gameCreate, errors: []error{game.ErrAlreadyPlaying, ErrTimeout}}, g.createGame)
+	handle(mux, g, route{path: gameDel, errors: []error{game.ErrBattleStarted}}, g.delGame)
+	handle(mux, g, route{path: getAvailableGames}, g.getAvailableGames)
+	handle(mux, g, route{path: gameJoin, errors: []error{game.ErrGameNotFound, game.ErrSelfPlay, game.ErrAlreadyPlaying}}, g.joinGame)
+	handle(mux, g, route{path: quickMatch, errors: []error{game.ErrAlreadyPlaying, game.ErrNotQueued, ErrTimeout}}, g.quickMatch)
+	handle(mux, g, route{path: saveGameResult, errors: []error{
+		game.ErrGameNotFound,
+		game.ErrNotStarted,
+		game.ErrNotParticipant,
+		game.ErrNotFinished,
+		game.ErrWrongResult,
+	}}, g.gameResult)
+	handle(mux, g, route{path: getUserStat}, g.getUserStat)
+	handle(mux, g, route{path: getHistory}, g.getHistory)
+	handle(mux, g, route{path: getLeaderboard}, g.getLeaderboard)
+	handle(mux, g, route{path: getReplay, errors: []error{game.ErrMatchNotFound}}, g.getReplay)
+	handle(mux, g, route{path: gameResume}, g.resume)
+	handle(mux, g, route{path: listLive}, g.listLiveGames)
+
+	mux.HandleFunc(urlPath(gameBattle), g.battle)
+	mux.HandleFunc(urlPath(gameWatch), g.watch)
+}
+
+// urlPath returns the path the requests of the AMQP queue are served at
+func urlPath(queue string) string {
+	return "/api/" + strings.ReplaceAll(queue, ".", "/")
+}
+
+// route describes how the requests of a path are served
+type route struct {
+	path string // the name of the AMQP queue of the same requests
+	// public routes are served without checking the session token,
+	// the handler authorizes the request itself if it needs to
+	public bool
+	// errors of the services shown to the client as they are, the other errors are reported as ErrInternal.
+	// ErrBadRequest and ErrUnauthorized are always shown.
+	errors []error
+}
+
+// request is a request being served
+type request struct {
+	ctx   context.Context // done when the client goes away
+	token string          // the session token, empty if the request has none
+	user  string          // the login from the session token, empty for the public routes
+	log   *slog.Logger
+}
+
+// validator is implemented by the requests which can be checked before they are handled
+type validator interface {
+	validate() error
+}
+
+// errorResponse is the body of every failed request
+type errorResponse struct {
+	Err string `json:"error"`
+}
+
+// handler serves a decoded and authorized request
+type handler[Req, Resp any] func(r *request, req Req) (Resp, error)
+
+// handle registers the handler of the route. The requests are POSTed with a JSON body, an empty body is a zero request.
+func handle[Req, Resp any](mux *http.ServeMux, g *Gateway, rt route, h handler[Req, Resp]) {
+	mux.HandleFunc(urlPath(rt.path), func(w http.ResponseWriter, hr *http.Request) {
+		if hr.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Err: http.StatusText(http.StatusMethodNotAllowed)})
+			return
+		}
+
+		r := &request{
+			ctx:   hr.Context(),
+			token: token(hr),
+			log:   g.log.With(slog.String("path", rt.path)),
+		}
+		resp, err := invoke(g, rt, r, http.MaxBytesReader(w, hr.Body, maxBodySize), h)
+		if err != nil {
+			shown := rt.expose(err)
+			if shown == ErrInternal {
+				r.log.Error("Request failed", slog.String("error", err.Error()))
+			} else {
+				r.log.Info("Request rejected", slog.String("error", err.Error()))
+			}
+			writeJSON(w, status(shown), errorResponse{Err: shown.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+// invoke decodes, authorizes and validates the request and passes it to the handler.
+// A panic of the handler is recovered and reported as ErrInternal.
+func invoke[Req, Resp any](g *Gateway, rt route, r *request, body io.Reader, h handler[Req, Resp]) (resp Resp, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			r.log.Error("Handler panicked", slog.String("panic", fmt.Sprint(p)), slog.String("stack", string(debug.Stack())))
+			var zero Resp
+			resp, err = zero, fmt.Errorf("handler panicked: %v", p)
+		}
+	}()
+
+	var req Req
+	if err := json.NewDecoder(body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return resp, fmt.Errorf("%w: malformed body: %s", ErrBadRequest, err)
+	}
+
+	if !rt.public {
+		r.user, err = g.authorize(r.token)
+		if err != nil {
+			return resp, err
+		}
+		r.log = r.log.With(slog.String("login", r.user))
+	}
+
+	if v, ok := any(req).(validator); ok {
+		if err := v.validate(); err != nil {
+			return resp, fmt.Errorf("%w: %s", ErrBadRequest, err)
+		}
+	}
+
+	return h(r, req)
+}
+
+// expose returns the error to show to the client
+func (rt route) expose(err error) error {
+	switch {
+	case errors.Is(err, ErrBadRequest):
+		return ErrBadRequest
+	case errors.Is(err, ErrUnauthorized):
+		return ErrUnauthorized
+	}
+	for _, shown := range rt.errors {
+		if errors.Is(err, shown) {
+			return shown
+		}
+	}
+	return ErrInternal
+}
+
+// status returns the HTTP status of the error shown to the client,
+// the errors of the game rules are conflicts with the state of the game
+func status(shown error) int {
+	switch shown {
+	case ErrBadRequest:
+		return http.StatusBadRequest
+	case ErrUnauthorized, auth.ErrWrongPass:
+		return http.StatusUnauthorized
+	case ErrTimeout:
+		return http.StatusRequestTimeout
+	case ErrInternal:
+		return http.StatusInternalServerError
+	case storage.ErrUserNotFound, game.ErrGameNotFound, game.ErrMatchNotFound:
+		return http.StatusNotFound
+	}
+	return http.StatusConflict
+}
+
+// token returns the session token of the request from the Authorization header,
+// or from the token query parameter as the browsers can't set the headers of the WebSockets
+func token(r *http.Request) string {
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return bearer
+	}
+	return r.URL.Query().Get("token")
+}
+
+// authorize returns the login of the user the session token is issued to
+func (g *Gateway) authorize(token string) (string, error) {
+	if token == "" {
+		return "", ErrUnauthorized
+	}
+	login, err := g.auth.ValidateToken(token)
+	if err != nil {
+		g.log.Info("Rejected session token", slog.String("reason", err.Error()))
+		return "", ErrUnauthorized
+	}
+	return login, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// Close stops accepting the requests, closes the WebSockets and waits for the requests in flight until ctx is done
+func (g *Gateway) Close(ctx context.Context) error {
+	g.cancel()
+	g.hub.closeAll()
+	return g.srv.Shutdown(ctx)
+}
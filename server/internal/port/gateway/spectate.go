@@ -0,0 +1,129 @@
+package gateway
+
+import (
+	"battle-ship_server/internal/service/game"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+type spectateService interface {
+	LiveGames() []game.LiveGame
+	Watch(gameID string) (game.SpectatorView, error)
+}
+
+func (g *Gateway) listLiveGames(_ *request, _ listLiveRequest) (listLiveResponse, error) {
+	live := g.game.LiveGames()
+	games := make([]liveGame, 0, len(live))
+	for _, lg := range live {
+		games = append(games, toLiveGame(lg))
+	}
+	return listLiveResponse{Games: games}, nil
+}
+
+// watch serves the WebSocket of a spectator of the game from the game_id query parameter.
+// The first message is the watchResponse with the state of the game, the events of the game follow as spectatorMessage.
+// The spectator sends nothing.
+func (g *Gateway) watch(w http.ResponseWriter, hr *http.Request) {
+	const op = "Gateway.watch"
+
+	login, err := g.authorize(token(hr))
+	if err != nil {
+		writeJSON(w, http.StatusUnauthorized, errorResponse{Err: err.Error()})
+		return
+	}
+	req := watchRequest{GameID: hr.URL.Query().Get("game_id")}
+	if err := req.validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Err: ErrBadRequest.Error()})
+		return
+	}
+	if _, err := g.game.Watch(req.GameID); err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{Err: game.ErrGameNotFound.Error()})
+		return
+	}
+
+	log := g.log.With(
+		slog.String("op", op),
+		slog.String("login", login),
+		slog.String("game_id", req.GameID),
+	)
+
+	ws, err := g.upgrader.Upgrade(w, hr, nil)
+	if err != nil {
+		log.Info("Failed to upgrade to a WebSocket", slog.String("error", err.Error()))
+		return // the upgrader has answered
+	}
+	c := newConn(ws, func() {})
+	defer c.close()
+
+	err = g.hub.addSpectator(req.GameID, c, func() (any, error) {
+		return g.watchState(req.GameID)
+	})
+	if errors.Is(err, game.ErrGameNotFound) {
+		return // ended before the WebSocket was opened
+	}
+	defer g.hub.removeSpectator(req.GameID, c)
+	log.Info("spectator joined")
+
+	// the messages are read for the pongs and the close of the client
+	for {
+		if _, err := c.read(); err != nil {
+			return
+		}
+	}
+}
+
+// watchState returns the state of the running game for a spectator who has just subscribed to its events
+func (g *Gateway) watchState(gameID string) (watchResponse, error) {
+	view, err := g.game.Watch(gameID)
+	if err != nil {
+		return watchResponse{}, err
+	}
+
+	shots := make(map[string][]shotInfo, len(view.SeaShots))
+	for user, s := range view.SeaShots {
+		shots[user] = shotInfos(s)
+	}
+	return watchResponse{
+		Game:  toLiveGame(view.LiveGame),
+		Turn:  view.Turn,
+		Shots: shots,
+	}, nil
+}
+
+func toLiveGame(lg game.LiveGame) liveGame {
+	return liveGame{
+		ID:        lg.ID,
+		User1:     lg.User1,
+		User2:     lg.User2,
+		Started:   lg.Started,
+		Shots:     lg.Shots,
+		StartedAt: lg.StartedAt,
+	}
+}
+
+func toSpectatorMessage(e game.Event) spectatorMessage {
+	msg := spectatorMessage{
+		GameID:   e.GameID,
+		Attacker: e.Attacker,
+		X:        e.X,
+		Y:        e.Y,
+		Hit:      e.Hit,
+		Destroy:  e.Destroy,
+		Winner:   e.Winner,
+		Reason:   string(e.Reason),
+	}
+	switch e.Type {
+	case game.EventReady:
+		msg.Type = ready
+	case game.EventShot:
+		msg.Type = result
+	case game.EventEnd:
+		msg.Type = end
+		msg.Fleets = make(map[string][][]point, len(e.Fleets))
+		for user, fleet := range e.Fleets {
+			msg.Fleets[user] = toPoints(fleet)
+		}
+	}
+	return msg
+}
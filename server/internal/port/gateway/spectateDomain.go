@@ -0,0 +1,61 @@
+package gateway
+
+import (
+	"errors"
+	"time"
+)
+
+type liveGame struct {
+	ID        string    `json:"id"`
+	User1     string    `json:"user1"`
+	User2     string    `json:"user2"`
+	Started   bool      `json:"started"`
+	Shots     int       `json:"shots"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+type listLiveRequest struct{}
+
+type listLiveResponse struct {
+	Games []liveGame `json:"games"`
+}
+
+// watchRequest is taken from the query of the WebSocket, the state of the game is its first message
+type watchRequest struct {
+	GameID string `json:"game_id"`
+}
+
+func (req watchRequest) validate() error {
+	if req.GameID == "" {
+		return errors.New("empty game id")
+	}
+	return nil
+}
+
+type watchResponse struct {
+	Game  liveGame              `json:"game"`
+	Turn  string                `json:"turn,omitempty"`
+	Shots map[string][]shotInfo `json:"shots,omitempty"` // user name -> shots at the sea of the user
+}
+
+// spectatorMessage structure:
+// ready { attacker } - the battle began, attacker shoots first;
+// result { attacker, x, y, hit, destroy };
+// end { attacker, x, y, hit, destroy, winner, reason, fleets }
+type spectatorMessage struct {
+	Type     messageType          `json:"type"`
+	GameID   string               `json:"game_id"`
+	Attacker string               `json:"attacker,omitempty"`
+	X        int                  `json:"x,omitempty"`
+	Y        int                  `json:"y,omitempty"`
+	Hit      bool                 `json:"hit,omitempty"`
+	Destroy  bool                 `json:"destroy,omitempty"`
+	Winner   string               `json:"winner,omitempty"`
+	Reason   string               `json:"reason,omitempty"`
+	Fleets   map[string][][]point `json:"fleets,omitempty"` // user name -> ships, revealed at the end
+}
+
+const ( // the names of the AMQP queues of the same requests
+	listLive  = "game.list_live"
+	gameWatch = "game.watch"
+)
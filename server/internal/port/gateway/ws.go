@@ -0,0 +1,212 @@
+package gateway
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait  = 10 * time.Second  // for a message to be written
+	pongWait   = 60 * time.Second  // for the pong of the client before the WebSocket is considered dead
+	pingPeriod = pongWait * 9 / 10 // between the pings, less than pongWait
+	sendBuffer = 64                // messages waiting to be written before the client is considered too slow
+)
+
+// conn is a WebSocket of a player or a spectator. The messages are written by a goroutine of its own,
+// so a slow client can't hold up the events of the others.
+type conn struct {
+	ws   *websocket.Conn
+	send chan any
+	done chan struct{}
+	once sync.Once
+}
+
+// newConn starts writing the messages pushed to the WebSocket and pinging the client.
+// onPong is called with every pong of the client.
+func newConn(ws *websocket.Conn, onPong func()) *conn {
+	c := &conn{
+		ws:   ws,
+		send: make(chan any, sendBuffer),
+		done: make(chan struct{}),
+	}
+	ws.SetReadLimit(maxBodySize)
+	_ = ws.SetReadDeadline(time.Now().Add(pongWait))
+	ws.SetPongHandler(func(string) error {
+		onPong()
+		return ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	go c.write()
+	return c
+}
+
+// push queues the message, the WebSocket is closed if the client can't keep up
+func (c *conn) push(msg any) {
+	select {
+	case <-c.done:
+	case c.send <- msg:
+	default:
+		c.close()
+	}
+}
+
+// close closes the WebSocket once the queued messages are written, it may be called many times
+func (c *conn) close() {
+	c.once.Do(func() {
+		close(c.done)
+	})
+}
+
+func (c *conn) write() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.ws.Close()
+	}()
+
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.ws.WriteJSON(msg); err != nil {
+				c.close()
+				return
+			}
+		case <-ticker.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				c.close()
+				return
+			}
+		case <-c.done:
+			for {
+				select {
+				case msg := <-c.send:
+					_ = c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+					if err := c.ws.WriteJSON(msg); err != nil {
+						return
+					}
+				default:
+					_ = c.ws.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(writeWait))
+					return
+				}
+			}
+		}
+	}
+}
+
+// read returns the next message of the client. The error means the WebSocket is closed.
+func (c *conn) read() ([]byte, error) {
+	_, msg, err := c.ws.ReadMessage()
+	return msg, err
+}
+
+// hub keeps the WebSockets the events of the games are delivered to
+type hub struct {
+	mu         sync.Mutex
+	players    map[string]*conn              // login -> the battle WebSocket of the player
+	spectators map[string]map[*conn]struct{} // game id -> the WebSockets watching the game
+}
+
+func newHub() *hub {
+	return &hub{
+		players:    make(map[string]*conn),
+		spectators: make(map[string]map[*conn]struct{}),
+	}
+}
+
+// addPlayer makes c the battle WebSocket of the player, the one the player had before is closed
+func (h *hub) addPlayer(login string, c *conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if old, ok := h.players[login]; ok {
+		old.close()
+	}
+	h.players[login] = c
+}
+
+// removePlayer forgets c unless the player has opened another WebSocket since
+func (h *hub) removePlayer(login string, c *conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.players[login] == c {
+		delete(h.players, login)
+	}
+}
+
+// dropPlayer closes the battle WebSocket of the player, e.g. after the logout
+func (h *hub) dropPlayer(login string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if c, ok := h.players[login]; ok {
+		c.close()
+		delete(h.players, login)
+	}
+}
+
+// toPlayer pushes the message to the battle WebSocket of the player, if the player has one
+func (h *hub) toPlayer(login string, msg any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if c, ok := h.players[login]; ok {
+		c.push(msg)
+	}
+}
+
+// addSpectator pushes the first message returned by state and subscribes c to the events of the game.
+// No event of the game is delivered in between.
+func (h *hub) addSpectator(gameID string, c *conn, state func() (any, error)) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	msg, err := state()
+	if err != nil {
+		return err
+	}
+	c.push(msg)
+
+	if h.spectators[gameID] == nil {
+		h.spectators[gameID] = make(map[*conn]struct{})
+	}
+	h.spectators[gameID][c] = struct{}{}
+	return nil
+}
+
+func (h *hub) removeSpectator(gameID string, c *conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.spectators[gameID], c)
+	if len(h.spectators[gameID]) == 0 {
+		delete(h.spectators, gameID)
+	}
+}
+
+func (h *hub) toSpectators(gameID string, msg any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for c := range h.spectators[gameID] {
+		c.push(msg)
+	}
+}
+
+// closeAll closes every WebSocket, the server is stopping
+func (h *hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, c := range h.players {
+		c.close()
+	}
+	for _, watching := range h.spectators {
+		for c := range watching {
+			c.close()
+		}
+	}
+}
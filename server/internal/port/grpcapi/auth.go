@@ -0,0 +1,59 @@
+package grpcapi
+
+import (
+	pb "battle-ship_server/api/battleship/v1"
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+type authService interface {
+	Login(ctx context.Context, username, password string) (token string, err error)
+	Register(ctx context.Context, username, password string) (token string, err error)
+	Logout(token string) (login string, err error)
+	ValidateToken(token string) (login string, err error)
+}
+
+type authServer struct {
+	pb.UnimplementedAuthServiceServer
+	s *Server
+}
+
+func (a *authServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	if req.GetUsername() == "" || req.GetPassword() == "" {
+		return nil, fmt.Errorf("%w: empty username or password", ErrBadRequest)
+	}
+	token, err := a.s.auth.Register(ctx, req.GetUsername(), req.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RegisterResponse{Token: token}, nil
+}
+
+func (a *authServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	if req.GetUsername() == "" || req.GetPassword() == "" {
+		return nil, fmt.Errorf("%w: empty username or password", ErrBadRequest)
+	}
+	token, err := a.s.auth.Login(ctx, req.GetUsername(), req.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.LoginResponse{Token: token}, nil
+}
+
+// Logout revokes the session of the token in the metadata itself, so the method is public
+func (a *authServer) Logout(ctx context.Context, _ *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	login, err := a.s.auth.Logout(token(ctx))
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	// the user can't come back to the games without a session
+	err = a.s.game.LeaveGames(ctx, login)
+	if err != nil {
+		a.s.log.Error("Failed to leave games", slog.String("login", login), slog.String("error", err.Error()))
+	}
+	a.s.players.drop(login)
+
+	return &pb.LogoutResponse{}, nil
+}
@@ -0,0 +1,271 @@
+package grpcapi
+
+import (
+	pb "battle-ship_server/api/battleship/v1"
+	"battle-ship_server/internal/service/game"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type battleService interface {
+	PlaceFleet(userName string, fleet [][]game.Point) error
+	Attack(ctx context.Context, userName string, x, y int) error
+	Heartbeat(userName string) error
+	Chat(userName, text string) error
+	Events() <-chan game.Event
+}
+
+// sendBuffer is how many events may wait to be sent before the player is considered too slow
+const sendBuffer = 64
+
+// player is the battle stream of a user
+type player struct {
+	send chan *pb.BattleResponse
+	done chan struct{}
+	once sync.Once
+}
+
+func newPlayer() *player {
+	return &player{
+		send: make(chan *pb.BattleResponse, sendBuffer),
+		done: make(chan struct{}),
+	}
+}
+
+// push queues the event, the stream is ended if the player can't keep up
+func (p *player) push(resp *pb.BattleResponse) {
+	select {
+	case <-p.done:
+	case p.send <- resp:
+	default:
+		p.close()
+	}
+}
+
+// close ends the stream, it may be called many times
+func (p *player) close() {
+	p.once.Do(func() {
+		close(p.done)
+	})
+}
+
+// players keeps the battle streams the events of the games are delivered to
+type players struct {
+	mu      sync.Mutex
+	streams map[string]*player // login -> the battle stream of the user
+}
+
+func newPlayers() *players {
+	return &players{streams: make(map[string]*player)}
+}
+
+// add makes p the battle stream of the user, the one the user had before is ended
+func (ps *players) add(login string, p *player) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if old, ok := ps.streams[login]; ok {
+		old.close()
+	}
+	ps.streams[login] = p
+}
+
+// remove forgets p unless the user has opened another stream since
+func (ps *players) remove(login string, p *player) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.streams[login] == p {
+		delete(ps.streams, login)
+	}
+}
+
+// drop ends the battle stream of the user, e.g. after the logout
+func (ps *players) drop(login string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if p, ok := ps.streams[login]; ok {
+		p.close()
+		delete(ps.streams, login)
+	}
+}
+
+// push sends the event to the battle stream of the user, if the user has one
+func (ps *players) push(login string, resp *pb.BattleResponse) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if p, ok := ps.streams[login]; ok {
+		p.push(resp)
+	}
+}
+
+// closeAll ends every battle stream, the server is stopping
+func (ps *players) closeAll() {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for _, p := range ps.streams {
+		p.close()
+	}
+}
+
+// Battle passes the moves of the user to the game service and sends the events of the running game back.
+// The stream is ended when the user opens another one, logs out or the server stops.
+func (g *gameServer) Battle(stream pb.GameService_BattleServer) error {
+	const op = "grpcapi.Battle"
+
+	user := login(stream.Context())
+	log := g.s.log.With(
+		slog.String("op", op),
+		slog.String("login", user),
+	)
+
+	p := newPlayer()
+	g.s.players.add(user, p)
+	defer g.s.players.remove(user, p)
+	log.Debug("Player connected")
+
+	// the moves are received by a goroutine of their own so the events are sent while the player thinks,
+	// the stream is only sent to from this one
+	moves := make(chan *pb.BattleRequest)
+	received := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				received <- err
+				return
+			}
+			select {
+			case moves <- req:
+			case <-stream.Context().Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-p.done:
+			return status.Error(codes.Aborted, "the battle stream is replaced or closed")
+		case err := <-received:
+			log.Debug("Player disconnected")
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case req := <-moves:
+			if err := g.s.move(user, req); err != nil {
+				shown := expose(err, battleErrors)
+				if shown == ErrInternal {
+					log.Error("Move failed", slog.String("error", err.Error()))
+				} else {
+					log.Info("Move rejected", slog.String("error", err.Error()))
+				}
+				err = stream.Send(&pb.BattleResponse{Event: &pb.BattleResponse_Error_{Error: &pb.BattleResponse_Error{
+					Move:    moveName(req),
+					Message: shown.Error(),
+				}}})
+				if err != nil {
+					return err
+				}
+			}
+		case resp := <-p.send:
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// move passes the move of the user to the game service
+func (s *Server) move(user string, req *pb.BattleRequest) error {
+	switch m := req.GetMove().(type) {
+	case *pb.BattleRequest_Heartbeat_:
+		_ = s.game.Heartbeat(user) // a late heartbeat of a finished game is not an error
+		return nil
+	case *pb.BattleRequest_PlaceFleet_:
+		fleet := make([][]game.Point, 0, len(m.PlaceFleet.GetShips()))
+		for _, ship := range m.PlaceFleet.GetShips() {
+			cells := make([]game.Point, 0, len(ship.GetCells()))
+			for _, p := range ship.GetCells() {
+				cells = append(cells, game.Point{X: int(p.GetX()), Y: int(p.GetY())})
+			}
+			fleet = append(fleet, cells)
+		}
+		return s.game.PlaceFleet(user, fleet)
+	case *pb.BattleRequest_Attack_:
+		return s.game.Attack(s.ctx, user, int(m.Attack.GetX()), int(m.Attack.GetY()))
+	case *pb.BattleRequest_Chat_:
+		return s.game.Chat(user, m.Chat.GetText())
+	}
+	return ErrBadRequest
+}
+
+// moveName returns the name of the move field of the request
+func moveName(req *pb.BattleRequest) string {
+	switch req.GetMove().(type) {
+	case *pb.BattleRequest_Heartbeat_:
+		return "heartbeat"
+	case *pb.BattleRequest_PlaceFleet_:
+		return "place_fleet"
+	case *pb.BattleRequest_Attack_:
+		return "attack"
+	case *pb.BattleRequest_Chat_:
+		return "chat"
+	}
+	return ""
+}
+
+// GameEvents delivers the events of the game service to the battle streams until the channel of the events is closed.
+// The start of a game wakes up the calls waiting for the opponent.
+// The events for the spectators are dropped, the games are not watched over gRPC.
+func (s *Server) GameEvents() {
+	for e := range s.game.Events() {
+		if e.To == "" {
+			continue
+		}
+		if e.Type == game.EventStarted {
+			s.waiters.wake(e)
+			continue
+		}
+
+		var resp *pb.BattleResponse
+		switch e.Type {
+		case game.EventReady:
+			resp = &pb.BattleResponse{Event: &pb.BattleResponse_Ready_{Ready: &pb.BattleResponse_Ready{First: e.First}}}
+		case game.EventShot:
+			resp = &pb.BattleResponse{Event: &pb.BattleResponse_Shot_{Shot: toShot(e)}}
+		case game.EventEnd:
+			end := &pb.BattleResponse_End{Winner: e.Winner, Reason: string(e.Reason)}
+			if e.Attacker != "" {
+				end.Shot = toShot(e)
+			}
+			resp = &pb.BattleResponse{Event: &pb.BattleResponse_End_{End: end}}
+		case game.EventChat:
+			resp = &pb.BattleResponse{Event: &pb.BattleResponse_Chat_{Chat: &pb.BattleResponse_Chat{From: e.From, Text: e.Text}}}
+		default:
+			continue
+		}
+		// the players of the other ports get the event there
+		s.players.push(e.To, resp)
+	}
+}
+
+func toShot(e game.Event) *pb.BattleResponse_Shot {
+	return &pb.BattleResponse_Shot{
+		Attacker: e.Attacker,
+		X:        int32(e.X),
+		Y:        int32(e.Y),
+		Hit:      e.Hit,
+		Destroy:  e.Destroy,
+	}
+}
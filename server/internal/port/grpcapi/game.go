@@ -0,0 +1,208 @@
+package grpcapi
+
+import (
+	pb "battle-ship_server/api/battleship/v1"
+	"battle-ship_server/internal/service/game"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"sync"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type gameService interface {
+	CreateGame(userName string) error
+	DelGame(userName string) (user2 string, err error)
+	GetAvailableGames() (games []string, err error)
+	JoinGame(creatorUserName, joiningUserName string) error
+	GetUserStat(ctx context.Context, userName string) (game.Statistics, error)
+	LeaveGames(ctx context.Context, userName string) error
+	GetHistory(ctx context.Context, userName string, page, pageSize int) (matches []game.Match, total int, err error)
+	GetLeaderboard(ctx context.Context, userName string, page, pageSize int) (entries []game.LeaderboardEntry, total int, userRank int, err error)
+	battleService
+}
+
+type gameServer struct {
+	pb.UnimplementedGameServiceServer
+	s *Server
+}
+
+// waiters are the calls of the users waiting for an opponent, GameEvents wakes them up on EventStarted
+type waiters struct {
+	mu    sync.Mutex
+	chans map[string][]chan game.Event // login -> the calls of the user
+}
+
+func newWaiters() *waiters {
+	return &waiters{chans: make(map[string][]chan game.Event)}
+}
+
+// add returns the channel the start of the next game of the user is sent to
+func (w *waiters) add(login string) chan game.Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch := make(chan game.Event, 1)
+	w.chans[login] = append(w.chans[login], ch)
+	return ch
+}
+
+// remove forgets the call, it may be woken up already
+func (w *waiters) remove(login string, ch chan game.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	chans := slices.DeleteFunc(w.chans[login], func(c chan game.Event) bool { return c == ch })
+	if len(chans) == 0 {
+		delete(w.chans, login)
+		return
+	}
+	w.chans[login] = chans
+}
+
+// wake sends the start of the game to the calls of the player, every channel gets a single event
+func (w *waiters) wake(e game.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, ch := range w.chans[e.To] {
+		ch <- e
+	}
+	delete(w.chans, e.To)
+}
+
+// CreateGame answers once another user joins the game. The game is deleted if nobody joins
+// before the deadline of the call or the wait timeout.
+func (g *gameServer) CreateGame(ctx context.Context, _ *pb.CreateGameRequest) (*pb.CreateGameResponse, error) {
+	user := login(ctx)
+	log := g.s.log.With(slog.String("login", user))
+
+	started := g.s.waiters.add(user)
+	defer g.s.waiters.remove(user, started)
+
+	err := g.s.game.CreateGame(user)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Game created successfully")
+
+	e, err := g.waitForOpponent(ctx, started)
+	if err != nil {
+		if _, err := g.s.game.DelGame(user); err != nil {
+			log.Error("Failed to delete the game", slog.String("error", err.Error()))
+		}
+		return nil, err
+	}
+	return &pb.CreateGameResponse{Opponent: e.Opponent}, nil
+}
+
+// waitForOpponent waits for the start of the game of the user
+func (g *gameServer) waitForOpponent(ctx context.Context, started <-chan game.Event) (game.Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.s.cfg.WaitTimeout)
+	defer cancel()
+
+	select {
+	case e := <-started:
+		return e, nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return game.Event{}, ErrTimeout
+		}
+		return game.Event{}, ctx.Err()
+	}
+}
+
+// JoinGame answers the joining user at once, the creator is told about the start on the port it waits on
+func (g *gameServer) JoinGame(ctx context.Context, req *pb.JoinGameRequest) (*pb.JoinGameResponse, error) {
+	if req.GetCreatorUserName() == "" {
+		return nil, fmt.Errorf("%w: empty creator user name", ErrBadRequest)
+	}
+	err := g.s.game.JoinGame(req.GetCreatorUserName(), login(ctx))
+	if err != nil {
+		return nil, err
+	}
+	g.s.log.Info("Game joined", slog.String("login", login(ctx)), slog.String("creator", req.GetCreatorUserName()))
+	return &pb.JoinGameResponse{}, nil
+}
+
+func (g *gameServer) ListGames(_ context.Context, _ *pb.ListGamesRequest) (*pb.ListGamesResponse, error) {
+	games, err := g.s.game.GetAvailableGames()
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ListGamesResponse{Creators: games}, nil
+}
+
+func (g *gameServer) DelGame(ctx context.Context, _ *pb.DelGameRequest) (*pb.DelGameResponse, error) {
+	_, err := g.s.game.DelGame(login(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DelGameResponse{}, nil
+}
+
+func (g *gameServer) GetUserStat(ctx context.Context, req *pb.GetUserStatRequest) (*pb.GetUserStatResponse, error) {
+	if req.GetUserName() == "" {
+		return nil, fmt.Errorf("%w: empty user name", ErrBadRequest)
+	}
+	stat, err := g.s.game.GetUserStat(ctx, req.GetUserName())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetUserStatResponse{
+		Rating: int32(math.Round(stat.Rating.Value)),
+		Wins:   int32(stat.Wins),
+		Losses: int32(stat.Losses),
+	}, nil
+}
+
+func (g *gameServer) GetHistory(ctx context.Context, req *pb.GetHistoryRequest) (*pb.GetHistoryResponse, error) {
+	userName := login(ctx)
+	if req.GetUserName() != "" {
+		userName = req.GetUserName()
+	}
+
+	matches, total, err := g.s.game.GetHistory(ctx, userName, int(req.GetPage()), int(req.GetPageSize()))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.GetHistoryResponse{Matches: make([]*pb.Match, 0, len(matches)), Total: int32(total)}
+	for _, m := range matches {
+		resp.Matches = append(resp.Matches, &pb.Match{
+			Id:                m.ID,
+			Winner:            m.Winner,
+			Loser:             m.Loser,
+			StartedAt:         timestamppb.New(m.StartedAt),
+			EndedAt:           timestamppb.New(m.EndedAt),
+			Shots:             int32(m.Shots),
+			WinnerRatingDelta: m.WinnerRatingDelta,
+			LoserRatingDelta:  m.LoserRatingDelta,
+			EndReason:         string(m.EndReason),
+		})
+	}
+	return resp, nil
+}
+
+func (g *gameServer) GetLeaderboard(ctx context.Context, req *pb.GetLeaderboardRequest) (*pb.GetLeaderboardResponse, error) {
+	entries, total, rank, err := g.s.game.GetLeaderboard(ctx, login(ctx), int(req.GetPage()), int(req.GetPageSize()))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.GetLeaderboardResponse{Entries: make([]*pb.LeaderboardEntry, 0, len(entries)), Total: int32(total), MyRank: int32(rank)}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, &pb.LeaderboardEntry{
+			Rank:     int32(e.Rank),
+			UserName: e.Login,
+			Rating:   int32(math.Round(e.Stat.Rating.Value)),
+			Wins:     int32(e.Stat.Wins),
+			Losses:   int32(e.Stat.Losses),
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,298 @@
+// Package grpcapi serves the typed API of battleship.v1 for the tools and the bots, see api/battleship/v1.
+package grpcapi
+
+import (
+	pb "battle-ship_server/api/battleship/v1"
+	"battle-ship_server/internal/service/auth"
+	"battle-ship_server/internal/service/game"
+	"battle-ship_server/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrInternal     = errors.New("internal error")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrTimeout      = errors.New("timeout")
+)
+
+type Config struct {
+	Address string
+	// WaitTimeout is how long the creator of a game waits for the opponent unless the call has an earlier deadline
+	WaitTimeout time.Duration
+}
+
+type Server struct {
+	cfg     Config
+	srv     *grpc.Server
+	players *players
+	waiters *waiters
+	log     *slog.Logger
+
+	// ctx is passed to the services for the moves of the battles, it is cancelled on Close
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	auth authService
+	game gameService
+}
+
+func New(cfg Config, log *slog.Logger, auth authService, game gameService) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		cfg:     cfg,
+		players: newPlayers(),
+		waiters: newWaiters(),
+		log:     log,
+		ctx:     ctx,
+		cancel:  cancel,
+		auth:    auth,
+		game:    game,
+	}
+	s.srv = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.recoverUnary, s.authorizeUnary, s.exposeUnary),
+		grpc.ChainStreamInterceptor(s.recoverStream, s.authorizeStream),
+	)
+	pb.RegisterAuthServiceServer(s.srv, &authServer{s: s})
+	pb.RegisterGameServiceServer(s.srv, &gameServer{s: s})
+	// lets grpcurl and the like call the API without the .proto
+	reflection.Register(s.srv)
+	return s
+}
+
+// Run starts serving the calls and delivering the events of the game service to the battle streams
+func (s *Server) Run() error {
+	const op = "grpcapi.Run"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("address", s.cfg.Address),
+	)
+
+	lis, err := net.Listen("tcp", s.cfg.Address)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	go s.GameEvents()
+	go func() {
+		if err := s.srv.Serve(lis); err != nil {
+			log.Error("gRPC server stopped", slog.String("error", err.Error()))
+		}
+	}()
+	log.Info("gRPC server started")
+	return nil
+}
+
+// Close ends the battle streams and waits for the calls in flight until ctx is done, the rest are cut off
+func (s *Server) Close(ctx context.Context) {
+	s.cancel()
+	s.players.closeAll()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.srv.Stop()
+	}
+}
+
+// publicMethods are called without a session token,
+// Logout takes the token to revoke from the metadata itself
+var publicMethods = []string{
+	pb.AuthService_Register_FullMethodName,
+	pb.AuthService_Login_FullMethodName,
+	pb.AuthService_Logout_FullMethodName,
+}
+
+// methodErrors are the errors of the services shown to the client as they are, the other errors are reported
+// as ErrInternal. ErrBadRequest, ErrUnauthorized and the errors of the context of the call are always shown.
+var methodErrors = map[string][]error{
+	pb.AuthService_Register_FullMethodName:    {storage.ErrUserExists},
+	pb.AuthService_Login_FullMethodName:       {auth.ErrWrongPass, storage.ErrUserNotFound},
+	pb.GameService_CreateGame_FullMethodName:  {game.ErrAlreadyPlaying, ErrTimeout},
+	pb.GameService_JoinGame_FullMethodName:    {game.ErrGameNotFound, game.ErrSelfPlay, game.ErrAlreadyPlaying},
+	pb.GameService_DelGame_FullMethodName:     {game.ErrBattleStarted},
+	pb.GameService_GetUserStat_FullMethodName: {storage.ErrUserNotFound},
+}
+
+// battleErrors are the errors of the moves shown to the player, the battle goes on after them
+var battleErrors = []error{
+	game.ErrGameNotFound,
+	game.ErrNotYourTurn,
+	game.ErrFleetPlaced,
+	game.ErrNotStarted,
+	game.ErrBadFleet,
+	game.ErrBadShot,
+	game.ErrAlreadyShot,
+	game.ErrChatEmpty,
+	game.ErrChatTooLong,
+	game.ErrChatTooFast,
+}
+
+type loginKey struct{}
+
+// login returns the login of the user the session token of the call is issued to
+func login(ctx context.Context) string {
+	l, _ := ctx.Value(loginKey{}).(string)
+	return l
+}
+
+// token returns the session token from the "authorization" metadata of the call
+func token(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if bearer, ok := strings.CutPrefix(v, "Bearer "); ok {
+			return bearer
+		}
+	}
+	return ""
+}
+
+// authorize returns the context of the call with the login of the user the session token is issued to
+func (s *Server) authorize(ctx context.Context, method string) (context.Context, error) {
+	for _, m := range publicMethods {
+		if m == method {
+			return ctx, nil
+		}
+	}
+
+	t := token(ctx)
+	if t == "" {
+		return nil, ErrUnauthorized
+	}
+	l, err := s.auth.ValidateToken(t)
+	if err != nil {
+		s.log.Info("Rejected session token", slog.String("reason", err.Error()))
+		return nil, ErrUnauthorized
+	}
+	return context.WithValue(ctx, loginKey{}, l), nil
+}
+
+func (s *Server) authorizeUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return handler(ctx, req)
+}
+
+// authStream passes the context with the login of the user to the handler of the stream
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (a authStream) Context() context.Context {
+	return a.ctx
+}
+
+func (s *Server) authorizeStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return handler(srv, authStream{ServerStream: ss, ctx: ctx})
+}
+
+// exposeUnary turns the error of the call into the status shown to the client
+func (s *Server) exposeUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err == nil {
+		return resp, nil
+	}
+
+	log := s.log.With(
+		slog.String("method", info.FullMethod),
+		slog.String("login", login(ctx)),
+	)
+	shown := expose(err, methodErrors[info.FullMethod])
+	if shown == ErrInternal {
+		log.Error("Call failed", slog.String("error", err.Error()))
+	} else {
+		log.Info("Call rejected", slog.String("error", err.Error()))
+	}
+	return nil, status.Error(code(shown), shown.Error())
+}
+
+// expose returns the error to show to the client
+func expose(err error, shown []error) error {
+	switch {
+	case errors.Is(err, ErrBadRequest):
+		return ErrBadRequest
+	case errors.Is(err, ErrUnauthorized):
+		return ErrUnauthorized
+	case errors.Is(err, context.Canceled):
+		return context.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return context.DeadlineExceeded
+	}
+	for _, e := range shown {
+		if errors.Is(err, e) {
+			return e
+		}
+	}
+	return ErrInternal
+}
+
+// code returns the status code of the error shown to the client,
+// the errors of the game rules are failed preconditions of the state of the game
+func code(shown error) codes.Code {
+	switch shown {
+	case ErrBadRequest:
+		return codes.InvalidArgument
+	case ErrUnauthorized, auth.ErrWrongPass:
+		return codes.Unauthenticated
+	case ErrTimeout, context.DeadlineExceeded:
+		return codes.DeadlineExceeded
+	case context.Canceled:
+		return codes.Canceled
+	case ErrInternal:
+		return codes.Internal
+	case storage.ErrUserNotFound, game.ErrGameNotFound, game.ErrMatchNotFound:
+		return codes.NotFound
+	case storage.ErrUserExists:
+		return codes.AlreadyExists
+	}
+	return codes.FailedPrecondition
+}
+
+// recoverUnary reports a panic of the handler as ErrInternal
+func (s *Server) recoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			s.log.Error("Handler panicked", slog.String("method", info.FullMethod),
+				slog.String("panic", fmt.Sprint(p)), slog.String("stack", string(debug.Stack())))
+			resp, err = nil, status.Error(codes.Internal, ErrInternal.Error())
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func (s *Server) recoverStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			s.log.Error("Handler panicked", slog.String("method", info.FullMethod),
+				slog.String("panic", fmt.Sprint(p)), slog.String("stack", string(debug.Stack())))
+			err = status.Error(codes.Internal, ErrInternal.Error())
+		}
+	}()
+	return handler(srv, ss)
+}
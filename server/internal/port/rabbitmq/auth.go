@@ -1,9 +1,7 @@
 package rabbitmq
 
 import (
-	"battle-ship_server/internal/service/auth"
-	"battle-ship_server/internal/storage"
-	"encoding/json"
+	"context"
 	"errors"
 	"log/slog"
 )
@@ -18,176 +16,80 @@ type registerRequest struct {
 	Password string `json:"password"`
 }
 
+func (req loginRequest) validate() error {
+	if req.Username == "" || req.Password == "" {
+		return errors.New("empty username or password")
+	}
+	return nil
+}
+
 type loginResponse struct {
-	Err string `json:"error,omitempty"`
+	Token string `json:"token,omitempty"`
+	reply
 }
 
-type registerResponse struct {
-	Err string `json:"error,omitempty"`
+func (req registerRequest) validate() error {
+	if req.Username == "" || req.Password == "" {
+		return errors.New("empty username or password")
+	}
+	return nil
 }
 
-type authService interface {
-	Login(username, password string) error
-	Register(username, password string) error
-	Logout(username string) error
+type registerResponse struct {
+	Token string `json:"token,omitempty"`
+	reply
 }
 
-func (r *RabbitMQ) Login() {
-	const op = "RabbitMQ.Login"
-
-	log := r.log.With(
-		slog.String("op", op),
-	)
-
-	q, err := r.ch.QueueDeclare(
-		"auth.login", // name
-		false,        // durable
-		false,        // delete when unused
-		false,        // exclusive
-		false,        // no-wait
-		nil,          // arguments
-	)
-	if err != nil {
-		log.Error("Failed to declare a queue: %v", err)
-		return
-	}
+// the session to end is taken from the token header
+type logoutRequest struct{}
 
-	msgs, err := r.ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
-	if err != nil {
-		log.Error("Failed to register a login consumer: %v", err)
-		return
-	}
+type logoutResponse struct {
+	reply
+}
 
-	for d := range msgs {
-		var request loginRequest
-		err := json.Unmarshal(d.Body, &request)
-		if err != nil {
-			r.log.Error("Failed to unmarshal login request: %v", err)
-			r.sendResp(d, ErrBadRequest)
-			continue
-		}
-
-		err = r.auth.Login(request.Username, request.Password)
-		if errors.Is(err, auth.ErrWrongPass) {
-			r.sendResp(d, loginResponse{Err: err.Error()})
-			continue
-		} else if errors.Is(err, storage.ErrUserNotFound) {
-			r.sendResp(d, loginResponse{Err: err.Error()})
-			continue
-		} else if err != nil {
-			r.sendResp(d, loginResponse{Err: ErrInternal.Error()})
-			continue
-		}
-
-		r.sendResp(d, loginResponse{})
-	}
+const ( // queue names
+	authLogin    = "auth.login"
+	authRegister = "auth.register"
+	authLogout   = "auth.logout"
+)
+
+type authService interface {
+	Login(ctx context.Context, username, password string) (token string, err error)
+	Register(ctx context.Context, username, password string) (token string, err error)
+	Logout(token string) (login string, err error)
+	ValidateToken(token string) (login string, err error)
 }
 
-func (r *RabbitMQ) Register() {
-	const op = "RabbitMQ.Register"
-
-	log := r.log.With(
-		slog.String("op", op),
-	)
-
-	q, err := r.ch.QueueDeclare(
-		"auth.register", // name
-		false,           // durable
-		false,           // delete when unused
-		false,           // exclusive
-		false,           // no-wait
-		nil,             // arguments
-	)
+func (r *RabbitMQ) login(c *call, req loginRequest) (loginResponse, error) {
+	token, err := r.auth.Login(c.ctx, req.Username, req.Password)
 	if err != nil {
-		log.Error("Failed to declare a queue: %v", err)
-		return
+		return loginResponse{}, err
 	}
+	return loginResponse{Token: token}, nil
+}
 
-	msgs, err := r.ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
+func (r *RabbitMQ) register(c *call, req registerRequest) (registerResponse, error) {
+	token, err := r.auth.Register(c.ctx, req.Username, req.Password)
 	if err != nil {
-		log.Error("Failed to register a consumer: %v", err)
-		return
-	}
-
-	for d := range msgs {
-		var request registerRequest
-		err := json.Unmarshal(d.Body, &request)
-		if err != nil {
-			r.log.Error("Failed to unmarshal request: %v", err)
-			r.sendResp(d, ErrBadRequest)
-			continue
-		}
-
-		err = r.auth.Register(request.Username, request.Password)
-		if errors.Is(err, storage.ErrUserExists) {
-			r.sendResp(d, registerResponse{Err: err.Error()})
-			continue
-		} else if err != nil {
-			r.sendResp(d, registerResponse{Err: ErrInternal.Error()})
-			continue
-		}
-
-		r.sendResp(d, registerResponse{})
+		return registerResponse{}, err
 	}
+	return registerResponse{Token: token}, nil
 }
 
-func (r *RabbitMQ) Logout() {
-	const op = "RabbitMQ.Logout"
-
-	log := r.log.With(
-		slog.String("op", op),
-	)
-
-	q, err := r.ch.QueueDeclare(
-		"logout", // name
-		false,    // durable
-		false,    // delete when unused
-		false,    // exclusive
-		false,    // no-wait
-		nil,      // arguments
-	)
+// logout revokes the session of the token header itself, so the endpoint is served without authorization
+func (r *RabbitMQ) logout(c *call, _ logoutRequest) (logoutResponse, error) {
+	token, _ := c.d.Headers[tokenHeader].(string)
+	login, err := r.auth.Logout(token)
 	if err != nil {
-		log.Error("Failed to declare a queue: %v", err)
-		return
+		return logoutResponse{}, ErrUnauthorized
 	}
 
-	msgs, err := r.ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
+	// the user can't come back to the games without a session
+	err = r.game.LeaveGames(c.ctx, login)
 	if err != nil {
-		log.Error("Failed to register a consumer: %v", err)
-		return
+		c.log.Error("Failed to leave games", slog.String("login", login), slog.String("error", err.Error()))
 	}
+	r.waiting.take(login)
 
-	for d := range msgs {
-		var clientID int
-		err := json.Unmarshal(d.Body, &clientID)
-		if err != nil {
-			r.log.Error("Failed to unmarshal request: %v", err)
-			r.sendResp(d, ErrBadRequest)
-			continue
-		}
-	}
+	return logoutResponse{}, nil
 }
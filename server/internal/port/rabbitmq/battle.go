@@ -0,0 +1,119 @@
+package rabbitmq
+
+import (
+	"battle-ship_server/internal/service/game"
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/streadway/amqp"
+)
+
+type battleService interface {
+	PlaceFleet(userName string, fleet [][]game.Point) error
+	Attack(ctx context.Context, userName string, x, y int) error
+	Heartbeat(userName string) error
+	Chat(userName, text string) error
+	Resume(userName string) (game.GameState, error)
+	Events() <-chan game.Event
+}
+
+// battle serves the moves of the players. The results come to the players' queues from GameEvents,
+// so only the errors are answered, with the type of the request.
+// The endpoint authorizes the requests itself as the heartbeats are never answered, not even with an error.
+func (r *RabbitMQ) battle(c *call, req battleRequest) (battleMessage, error) {
+	resp := battleMessage{Type: req.Type}
+	if req.Type == heartbeat {
+		c.skipReply()
+	}
+
+	userName, err := r.authorize(c.d)
+	if err != nil {
+		return resp, err
+	}
+
+	switch req.Type {
+	case heartbeat:
+		_ = r.game.Heartbeat(userName) // a late heartbeat of a finished game is not an error
+		return resp, nil
+	case ready:
+		fleet := make([][]game.Point, 0, len(req.Ships))
+		for _, ship := range req.Ships {
+			cells := make([]game.Point, 0, len(ship))
+			for _, p := range ship {
+				cells = append(cells, game.Point{X: p.X, Y: p.Y})
+			}
+			fleet = append(fleet, cells)
+		}
+		err = r.game.PlaceFleet(userName, fleet)
+	case attack:
+		err = r.game.Attack(c.ctx, userName, req.X, req.Y)
+	case chat:
+		err = r.game.Chat(userName, req.Text)
+	default:
+		err = ErrBadRequest
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	c.skipReply()
+	return resp, nil
+}
+
+// GameEvents delivers the events of the game service to the players' queues and to the spectators,
+// the start of a game answers the request waiting for the opponent
+func (r *RabbitMQ) GameEvents() {
+	for e := range r.game.Events() {
+		if e.To == "" {
+			r.sendToSpectators(e)
+			continue
+		}
+		if e.Type == game.EventStarted {
+			r.opponentFound(e)
+			continue
+		}
+
+		var msg battleMessage
+		switch e.Type {
+		case game.EventReady:
+			msg = battleMessage{Type: ready, First: e.First}
+		case game.EventShot:
+			msg = battleMessage{Type: result, Attacker: e.Attacker, X: e.X, Y: e.Y, Hit: e.Hit, Destroy: e.Destroy}
+		case game.EventEnd:
+			msg = battleMessage{Type: end, Attacker: e.Attacker, X: e.X, Y: e.Y, Hit: e.Hit, Destroy: e.Destroy, Winner: e.Winner, Reason: string(e.Reason)}
+		case game.EventChat:
+			msg = battleMessage{Type: chat, From: e.From, Text: e.Text}
+		}
+		r.sendToPlayer(e.To, msg)
+	}
+}
+
+// sendToPlayer publishes the message to the personal queue of the player
+func (r *RabbitMQ) sendToPlayer(userName string, msg any) {
+	const op = "RabbitMQ.sendToPlayer"
+
+	log := r.log.With(
+		slog.String("op", op),
+		slog.String("user_name", userName),
+	)
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		log.Error("Failed to marshal message", slog.String("error", err.Error()))
+		return
+	}
+
+	err = r.channel().Publish(
+		"",       // exchange
+		userName, // routing key
+		false,    // mandatory
+		false,    // immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		})
+	if err != nil {
+		log.Error("Failed to publish message", slog.String("error", err.Error()))
+	}
+}
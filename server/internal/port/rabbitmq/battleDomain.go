@@ -0,0 +1,79 @@
+package rabbitmq
+
+type messageType int
+
+const (
+	ready messageType = iota
+	attack
+	result
+	end
+	heartbeat // sent by the players during the battle, never answered
+	chat
+)
+
+type point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// battleRequest structure:
+// ready { ships };
+// attack { x, y };
+// heartbeat {};
+// chat { text }
+type battleRequest struct {
+	Type  messageType `json:"type"`
+	X     int         `json:"x,omitempty"`
+	Y     int         `json:"y,omitempty"`
+	Ships [][]point   `json:"ships,omitempty"`
+	Text  string      `json:"text,omitempty"`
+}
+
+// battleMessage structure:
+// ready { first };
+// result { attacker, x, y, hit, destroy }
+// end { attacker, x, y, hit, destroy, winner, reason };
+// chat { from, text }
+type battleMessage struct {
+	Type     messageType `json:"type"`
+	First    bool        `json:"first,omitempty"`
+	Attacker string      `json:"attacker,omitempty"`
+	X        int         `json:"x,omitempty"`
+	Y        int         `json:"y,omitempty"`
+	Hit      bool        `json:"hit,omitempty"`
+	Destroy  bool        `json:"destroy,omitempty"`
+	Winner   string      `json:"winner,omitempty"`
+	Reason   string      `json:"reason,omitempty"`
+	From     string      `json:"from,omitempty"`
+	Text     string      `json:"text,omitempty"`
+	reply
+}
+
+// resumeRequest asks for the state of the running game of the user,
+// or gives the game up if Forfeit is set
+type resumeRequest struct {
+	Forfeit bool `json:"forfeit,omitempty"`
+}
+
+type shotInfo struct {
+	X       int  `json:"x"`
+	Y       int  `json:"y"`
+	Hit     bool `json:"hit,omitempty"`
+	Destroy bool `json:"destroy,omitempty"`
+}
+
+type resumeResponse struct {
+	Opponent      string     `json:"opponent,omitempty"` // empty if the user has no running game
+	FleetPlaced   bool       `json:"fleet_placed,omitempty"`
+	Started       bool       `json:"started,omitempty"`
+	MyTurn        bool       `json:"my_turn,omitempty"`
+	Fleet         [][]point  `json:"fleet,omitempty"`
+	MyShots       []shotInfo `json:"my_shots,omitempty"`
+	OpponentShots []shotInfo `json:"opponent_shots,omitempty"`
+	reply
+}
+
+const ( // queue names
+	gameBattle = "game.battle"
+	gameResume = "game.resume"
+)
@@ -0,0 +1,338 @@
+package rabbitmq
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+// deadLetterExchange receives the requests the server gave up on,
+// the dead letter queue of every request queue is bound to it with the name of the request queue
+const deadLetterExchange = "dead_letters"
+
+// retriesHeader counts the attempts of a request failed by a transient error
+const retriesHeader = "x-retries"
+
+var ErrUnknownQueue = errors.New("unknown request queue")
+
+// deadLetterQueue returns the name of the dead letter queue of the request queue
+func deadLetterQueue(queue string) string {
+	return queue + ".dead"
+}
+
+// queueArgs are the arguments of a request queue, the rejected requests go to its dead letter queue
+func queueArgs(queue string) amqp.Table {
+	return amqp.Table{
+		"x-dead-letter-exchange":    deadLetterExchange,
+		"x-dead-letter-routing-key": queue,
+	}
+}
+
+// declareRequestQueue declares the request queue with its arguments. The arguments of a queue can't be changed,
+// so the queue declared by an older server without them is deleted and declared again.
+// The request queues are not durable, the requests waiting in them would be lost on the restart of the broker anyway.
+func declareRequestQueue(conn *amqp.Connection, queue string, log *slog.Logger) error {
+	declare := func(ch *amqp.Channel) error {
+		_, err := ch.QueueDeclare(
+			queue,            // name
+			false,            // durable
+			false,            // delete when unused
+			false,            // exclusive
+			false,            // no-wait
+			queueArgs(queue), // arguments
+		)
+		return err
+	}
+
+	err := withChannel(conn, declare)
+	var amqpErr *amqp.Error
+	if !errors.As(err, &amqpErr) || amqpErr.Code != amqp.PreconditionFailed {
+		return err
+	}
+
+	// the broker has closed the channel, the queue is declared again on a new one
+	log.Warn("The request queue has other arguments, declaring it again", slog.String("reason", amqpErr.Reason))
+	err = withChannel(conn, func(ch *amqp.Channel) error {
+		_, err := ch.QueueDelete(
+			queue, // name
+			false, // if unused
+			false, // if empty
+			false, // no-wait
+		)
+		if err != nil {
+			return err
+		}
+		return declare(ch)
+	})
+	if err != nil {
+		return fmt.Errorf("the queue %s has other arguments than the dead letter ones and can't be declared again, "+
+			"delete it with `rabbitmqctl delete_queue %s`: %w", queue, queue, err)
+	}
+	return nil
+}
+
+// withChannel calls f with a channel of its own, it is closed afterwards
+func withChannel(conn *amqp.Connection, f func(ch *amqp.Channel) error) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+	return f(ch)
+}
+
+// declareDeadLetters declares the dead letter exchange and the dead letter queue of the request queue.
+// They are durable so the requests wait for the inspection across the restarts of the broker.
+func declareDeadLetters(ch *amqp.Channel, queue string) (amqp.Queue, error) {
+	err := ch.ExchangeDeclare(
+		deadLetterExchange, // name
+		"direct",           // type
+		true,               // durable
+		false,              // auto-deleted
+		false,              // internal
+		false,              // no-wait
+		nil,                // arguments
+	)
+	if err != nil {
+		return amqp.Queue{}, fmt.Errorf("declare the dead letter exchange: %w", err)
+	}
+
+	q, err := ch.QueueDeclare(
+		deadLetterQueue(queue), // name
+		true,                   // durable
+		false,                  // delete when unused
+		false,                  // exclusive
+		false,                  // no-wait
+		nil,                    // arguments
+	)
+	if err != nil {
+		return amqp.Queue{}, fmt.Errorf("declare the dead letter queue: %w", err)
+	}
+
+	err = ch.QueueBind(
+		q.Name,             // queue name
+		queue,              // routing key
+		deadLetterExchange, // exchange
+		false,              // no-wait
+		nil,                // arguments
+	)
+	if err != nil {
+		return amqp.Queue{}, fmt.Errorf("bind the dead letter queue: %w", err)
+	}
+	return q, nil
+}
+
+// retries returns how many times the request has been sent again after a transient failure
+func retries(d amqp.Delivery) int {
+	switch n := d.Headers[retriesHeader].(type) {
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	}
+	return 0
+}
+
+// retry sends the request to the end of its queue again with the count of the attempts increased
+func (r *RabbitMQ) retry(c *call, queue string) error {
+	headers := make(amqp.Table, len(c.d.Headers)+1)
+	for k, v := range c.d.Headers {
+		headers[k] = v
+	}
+	headers[retriesHeader] = int32(retries(c.d) + 1)
+
+	return c.ch.Publish(
+		"",    // exchange
+		queue, // routing key
+		false, // mandatory
+		false, // immediate
+		amqp.Publishing{
+			Headers:       headers,
+			ContentType:   c.d.ContentType,
+			CorrelationId: c.d.CorrelationId,
+			ReplyTo:       c.d.ReplyTo,
+			Body:          c.d.Body,
+		})
+}
+
+// DeadLetter is a request moved to a dead letter queue
+type DeadLetter struct {
+	Queue         string    // the request queue
+	Reason        string    // why the broker dead-lettered the request, rejected for the requests the server gave up on
+	Count         int64     // how many times the request has been dead-lettered
+	Time          time.Time // when the request was dead-lettered first
+	Retries       int
+	CorrelationID string
+	Body          []byte
+}
+
+// DeadLetters inspects, replays and purges the dead letter queues, it is used by the maintenance commands
+type DeadLetters struct {
+	conn *amqp.Connection
+	ch   *amqp.Channel
+}
+
+// OpenDeadLetters connects to the broker
+func OpenDeadLetters(url string) (*DeadLetters, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	// the replayed requests are removed from the dead letter queue once the broker has taken them
+	if err := ch.Confirm(false); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &DeadLetters{conn: conn, ch: ch}, nil
+}
+
+// Queues returns the request queues
+func (dl *DeadLetters) Queues() []string {
+	return slices.Clone(requestQueues)
+}
+
+// Count returns the number of the dead-lettered requests of the queue
+func (dl *DeadLetters) Count(queue string) (int, error) {
+	if !slices.Contains(requestQueues, queue) {
+		return 0, ErrUnknownQueue
+	}
+	q, err := declareDeadLetters(dl.ch, queue)
+	if err != nil {
+		return 0, err
+	}
+	return q.Messages, nil
+}
+
+// Peek returns up to limit dead-lettered requests of the queue, they stay in the dead letter queue
+func (dl *DeadLetters) Peek(queue string, limit int) ([]DeadLetter, error) {
+	if !slices.Contains(requestQueues, queue) {
+		return nil, ErrUnknownQueue
+	}
+	if _, err := declareDeadLetters(dl.ch, queue); err != nil {
+		return nil, err
+	}
+
+	letters := make([]DeadLetter, 0, limit)
+	var last uint64
+	for len(letters) < limit {
+		d, ok, err := dl.ch.Get(deadLetterQueue(queue), false)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		last = d.DeliveryTag
+		letters = append(letters, toDeadLetter(queue, d))
+	}
+
+	if last != 0 {
+		// multiple, requeue
+		if err := dl.ch.Nack(last, true, true); err != nil {
+			return nil, err
+		}
+	}
+	return letters, nil
+}
+
+// Replay sends up to limit dead-lettered requests back to the queue with the count of the attempts reset,
+// all of them if limit is 0. It returns the number of the replayed requests.
+func (dl *DeadLetters) Replay(queue string, limit int) (int, error) {
+	if !slices.Contains(requestQueues, queue) {
+		return 0, ErrUnknownQueue
+	}
+	if _, err := declareDeadLetters(dl.ch, queue); err != nil {
+		return 0, err
+	}
+
+	confirms := dl.ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+	replayed := 0
+	for limit == 0 || replayed < limit {
+		d, ok, err := dl.ch.Get(deadLetterQueue(queue), false)
+		if err != nil {
+			return replayed, err
+		}
+		if !ok {
+			break
+		}
+
+		headers := make(amqp.Table, len(d.Headers))
+		for k, v := range d.Headers {
+			if k != retriesHeader && k != "x-death" {
+				headers[k] = v
+			}
+		}
+		err = dl.ch.Publish(
+			"",    // exchange
+			queue, // routing key
+			false, // mandatory
+			false, // immediate
+			amqp.Publishing{
+				Headers:       headers,
+				ContentType:   d.ContentType,
+				CorrelationId: d.CorrelationId,
+				ReplyTo:       d.ReplyTo,
+				Body:          d.Body,
+			})
+		if err == nil {
+			if confirm := <-confirms; !confirm.Ack {
+				err = errors.New("the broker did not take the request")
+			}
+		}
+		if err != nil {
+			_ = d.Nack(false, true)
+			return replayed, err
+		}
+
+		if err := d.Ack(false); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+	return replayed, nil
+}
+
+// Purge deletes the dead-lettered requests of the queue and returns their number
+func (dl *DeadLetters) Purge(queue string) (int, error) {
+	if !slices.Contains(requestQueues, queue) {
+		return 0, ErrUnknownQueue
+	}
+	if _, err := declareDeadLetters(dl.ch, queue); err != nil {
+		return 0, err
+	}
+	return dl.ch.QueuePurge(deadLetterQueue(queue), false)
+}
+
+func (dl *DeadLetters) Close() error {
+	return dl.conn.Close()
+}
+
+func toDeadLetter(queue string, d amqp.Delivery) DeadLetter {
+	letter := DeadLetter{
+		Queue:         queue,
+		Retries:       retries(d),
+		CorrelationID: d.CorrelationId,
+		Body:          d.Body,
+	}
+	// the broker adds a record of the dead-lettering for every queue the request has been dead-lettered from
+	deaths, _ := d.Headers["x-death"].([]interface{})
+	for _, death := range deaths {
+		t, ok := death.(amqp.Table)
+		if !ok || t["queue"] != queue {
+			continue
+		}
+		letter.Reason, _ = t["reason"].(string)
+		letter.Count, _ = t["count"].(int64)
+		letter.Time, _ = t["time"].(time.Time)
+	}
+	return letter
+}
@@ -2,350 +2,194 @@ package rabbitmq
 
 import (
 	"battle-ship_server/internal/service/game"
-	"encoding/json"
-	"fmt"
+	"context"
 	"log/slog"
+	"math"
+	"sync"
 
 	"github.com/streadway/amqp"
 )
 
 type gameService interface {
-	CreateGame(userName string, dUser *amqp.Delivery) error
+	CreateGame(userName string) error
 	DelGame(userName string) (user2 string, err error)
 	GetAvailableGames() (games []string, err error)
-	JoinGame(creatorUserName, joiningUserName string, dJoiningUser *amqp.Delivery) (dCreatorUserName *amqp.Delivery, err error)
-	SaveGameResult(winner, loser string) error
-	GetUserStat(userName string) (game.Statistics, error)
+	JoinGame(creatorUserName, joiningUserName string) error
+	SaveGameResult(submitter, winner, loser string) error
+	GetUserStat(ctx context.Context, userName string) (game.Statistics, error)
+	LeaveGames(ctx context.Context, userName string) error
+	GetHistory(ctx context.Context, userName string, page, pageSize int) (matches []game.Match, total int, err error)
+	GetLeaderboard(ctx context.Context, userName string, page, pageSize int) (entries []game.LeaderboardEntry, total int, userRank int, err error)
+	GetReplay(ctx context.Context, matchID int64) (game.Match, error)
+	battleService
+	matchmakingService
+	spectateService
 }
 
-func (r *RabbitMQ) CreateGame() {
-	const op = "RabbitMQ.CreateGame"
-
-	log := r.log.With(
-		slog.String("op", op),
-	)
-
-	q, err := r.ch.QueueDeclare(
-		gameCreate, // name
-		false,      // durable
-		false,      // delete when unused
-		false,      // exclusive
-		false,      // no-wait
-		nil,        // arguments
-	)
-	if err != nil {
-		log.Error("Failed to declare a queue: %v", err)
-		return
-	}
+// waitingRequest is a request answered once the game of the user starts, see opponentFound
+type waitingRequest struct {
+	d     amqp.Delivery
+	quick bool // the quick match request, answered with the rating of the opponent
+}
 
-	msgs, err := r.ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
-	if err != nil {
-		log.Error("Failed to register a consumer: %v", err)
-		return
-	}
+// waiting keeps the requests of the users waiting for an opponent
+type waiting struct {
+	mu       sync.Mutex
+	requests map[string]waitingRequest // login -> the request of the user, a new one replaces it
+}
 
-	for d := range msgs {
-		var req gameCreateRequest
-		err := json.Unmarshal(d.Body, &req)
-		if err != nil {
-			log.Error("Failed to unmarshal request: %v", err)
-			r.sendResp(d, gameCreateResponse{Err: ErrBadRequest.Error()})
-			continue
-		}
+func newWaiting() *waiting {
+	return &waiting{requests: make(map[string]waitingRequest)}
+}
 
-		err = r.game.CreateGame(req.UserName, &d)
-		if err != nil {
-			r.sendResp(d, gameCreateResponse{Err: ErrInternal.Error()})
-			continue
-		}
-		log.With("login", req.UserName).Info("Game created successfully")
-		// the creator is waiting for another user to join
-	}
+func (w *waiting) add(login string, req waitingRequest) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.requests[login] = req
 }
 
-func (r *RabbitMQ) JoinGame() {
-	const op = "RabbitMQ.JoinGame"
+// take removes the request of the user and returns it, if the user has one
+func (w *waiting) take(login string) (waitingRequest, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 
-	log := r.log.With(
-		slog.String("op", op),
-	)
+	req, ok := w.requests[login]
+	delete(w.requests, login)
+	return req, ok
+}
 
-	q, err := r.ch.QueueDeclare(
-		gameJoin, // name
-		false,    // durable
-		false,    // delete when unused
-		false,    // exclusive
-		false,    // no-wait
-		nil,      // arguments
-	)
+// createGame answers once another user joins the game, see opponentFound
+func (r *RabbitMQ) createGame(c *call, _ gameCreateRequest) (gameCreateResponse, error) {
+	r.waiting.add(c.user, waitingRequest{d: c.d})
+	err := r.game.CreateGame(c.user)
 	if err != nil {
-		log.Error("Failed to declare a queue: %v", err)
-		return
+		r.waiting.take(c.user)
+		return gameCreateResponse{}, err
 	}
+	c.log.Info("Game created successfully")
+	// the creator is waiting for another user to join
+	c.skipReply()
+	return gameCreateResponse{}, nil
+}
 
-	msgs, err := r.ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
+// joinGame answers the joining user at once, the creator is told about the start by opponentFound
+func (r *RabbitMQ) joinGame(c *call, req gameJoinRequest) (gameJoinResponse, error) {
+	err := r.game.JoinGame(req.CreatorUserName, c.user)
 	if err != nil {
-		log.Error("Failed to register a consumer: %v", err)
-		return
+		return gameJoinResponse{}, err
 	}
 
-	for d := range msgs {
-		var req gameJoinRequest
-		err := json.Unmarshal(d.Body, &req)
-		if err != nil {
-			log.Error("Failed to unmarshal request: %v", err)
-			r.sendResp(d, gameJoinResponse{Err: ErrBadRequest.Error()})
-			continue
-		}
-
-		dUser1, err := r.game.JoinGame(req.CreatorUserName, req.JoiningUserName, &d)
-		if err != nil {
-			r.sendResp(d, gameJoinResponse{Err: err.Error()})
-			continue
-		}
-
-		log.Info(fmt.Sprintf("Game joined: %v -> %v", req.JoiningUserName, req.CreatorUserName))
-		r.sendResp(*dUser1, gameCreateResponse{
-			User2: req.JoiningUserName,
-			Err:   "",
-		})
-		r.sendResp(d, gameJoinResponse{}) //
-	}
+	c.log.Info("Game joined", slog.String("creator", req.CreatorUserName))
+	return gameJoinResponse{}, nil
 }
 
-func (r *RabbitMQ) GetAvailableGames() {
-	const op = "RabbitMQ.GetAvailableGames"
-
-	log := r.log.With(
-		slog.String("op", op),
-	)
-
-	q, err := r.ch.QueueDeclare(
-		getAvailableGames, // name
-		false,             // durable
-		false,             // delete when unused
-		false,             // exclusive
-		false,             // no-wait
-		nil,               // arguments
-	)
-	if err != nil {
-		log.Error("Failed to declare a queue: %v", err)
+// opponentFound answers the request of the user waiting for the opponent of the started game.
+// The user who joined the game has no such request.
+func (r *RabbitMQ) opponentFound(e game.Event) {
+	req, ok := r.waiting.take(e.To)
+	if !ok {
 		return
 	}
-
-	msgs, err := r.ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
-	if err != nil {
-		log.Error("Failed to register a consumer: %v", err)
+	if req.quick {
+		r.sendResp(req.d, quickMatchResponse{
+			Opponent:       e.Opponent,
+			OpponentRating: int(math.Round(e.OpponentRating)),
+		})
 		return
 	}
+	r.sendResp(req.d, gameCreateResponse{User2: e.Opponent})
+}
 
-	for d := range msgs {
-		var req getAvailableGamesRequest
-		err := json.Unmarshal(d.Body, &req)
-		if err != nil {
-			log.Error("Failed to unmarshal request: %v", err)
-			r.sendResp(d, getAvailableGamesResponse{Err: ErrBadRequest.Error()})
-			continue
-		}
-
-		games, err := r.game.GetAvailableGames()
-		if err != nil {
-			r.sendResp(d, getAvailableGamesResponse{Err: ErrInternal.Error()})
-			continue
-		}
-
-		log.Info("Sent games: %v", games)
-		r.sendResp(d, getAvailableGamesResponse{Games: games})
+func (r *RabbitMQ) getAvailableGames(c *call, _ getAvailableGamesRequest) (getAvailableGamesResponse, error) {
+	games, err := r.game.GetAvailableGames()
+	if err != nil {
+		return getAvailableGamesResponse{}, err
 	}
-}
 
-func (r *RabbitMQ) GameResult() {
-	const op = "RabbitMQ.SaveGameResult"
+	c.log.Debug("Sent games", slog.Any("games", games))
+	return getAvailableGamesResponse{Games: games}, nil
+}
 
-	log := r.log.With(
-		slog.String("op", op),
-	)
+func (r *RabbitMQ) gameResult(c *call, req gameResultRequest) (gameResultResponse, error) {
+	return gameResultResponse{}, r.game.SaveGameResult(c.user, req.Winner, req.Loser)
+}
 
-	q, err := r.ch.QueueDeclare(
-		saveGameResult, // name
-		false,          // durable
-		false,          // delete when unused
-		false,          // exclusive
-		false,          // no-wait
-		nil,            // arguments
-	)
+func (r *RabbitMQ) getUserStat(c *call, req getStatRequest) (getStatResponse, error) {
+	stat, err := r.game.GetUserStat(c.ctx, req.UserName)
 	if err != nil {
-		log.Error("Failed to declare a queue: %v", err)
-		return
+		return getStatResponse{}, err
 	}
 
-	msgs, err := r.ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
+	c.log.Info("user stat sent", slog.String("user_name", req.UserName))
+	return getStatResponse{
+		Rating: int(math.Round(stat.Rating.Value)),
+		Wins:   stat.Wins,
+		Losses: stat.Losses,
+	}, nil
+}
+
+// delGame deletes the game of the user, the opponent who has joined it meanwhile gets the end of the game
+func (r *RabbitMQ) delGame(c *call, _ gameDelRequest) (gameDelResponse, error) {
+	user2, err := r.game.DelGame(c.user)
 	if err != nil {
-		log.Error("Failed to register a consumer: %v", err)
-		return
+		return gameDelResponse{}, err
 	}
+	r.waiting.take(c.user)
 
-	for d := range msgs {
-		var req gameResultRequest
-		err := json.Unmarshal(d.Body, &req)
-		if err != nil {
-			log.Error("Failed to unmarshal request: %v", err)
-			r.sendResp(d, gameResultResponse{Err: ErrBadRequest.Error()})
-			continue
-		}
-
-		err = r.game.SaveGameResult(req.Winner, req.Loser)
-		if err != nil {
-			r.sendResp(d, gameResultResponse{Err: ErrInternal.Error()})
-			continue
-		}
-
-		r.sendResp(d, gameResultResponse{})
-	}
+	c.log.Info("game deleted", slog.String("user2", user2))
+	return gameDelResponse{}, nil
 }
 
-func (r *RabbitMQ) GetUserStat() {
-	const op = "RabbitMQ.GetUserStat"
-
-	log := r.log.With(
-		slog.String("op", op),
-	)
-
-	q, err := r.ch.QueueDeclare(
-		getUserStat, // name
-		false,       // durable
-		false,       // delete when unused
-		false,       // exclusive
-		false,       // no-wait
-		nil,         // arguments
-	)
-	if err != nil {
-		log.Error("Failed to declare a queue: %v", err)
-		return
+func (r *RabbitMQ) getHistory(c *call, req getHistoryRequest) (getHistoryResponse, error) {
+	userName := c.user
+	if req.UserName != "" {
+		userName = req.UserName
 	}
 
-	msgs, err := r.ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
+	matches, total, err := r.game.GetHistory(c.ctx, userName, req.Page, req.PageSize)
 	if err != nil {
-		log.Error("Failed to register a consumer: %v", err)
-		return
+		return getHistoryResponse{}, err
 	}
 
-	for d := range msgs {
-		var req getStatRequest
-		err := json.Unmarshal(d.Body, &req)
-		if err != nil {
-			log.Error("Failed to unmarshal request: %v", err)
-			r.sendResp(d, getStatResponse{Err: ErrBadRequest.Error()})
-			continue
-		}
-
-		stat, err := r.game.GetUserStat(req.UserName)
-		if err != nil {
-			r.sendResp(d, getStatResponse{Err: ErrInternal.Error()})
-			continue
-		}
-
-		r.sendResp(d, getStatResponse{
-			Rating: stat.Rating,
-			Wins:   stat.Wins,
-			Losses: stat.Losses,
-		})
-
-		log.With("login", req.UserName).Info("user stat sent")
+	resp := getHistoryResponse{Matches: make([]matchInfo, 0, len(matches)), Total: total}
+	for _, m := range matches {
+		resp.Matches = append(resp.Matches, toMatchInfo(m))
 	}
+	c.log.Info("history sent", slog.String("user_name", userName))
+	return resp, nil
 }
 
-func (r *RabbitMQ) DelGame() {
-	const op = "RabbitMQ.DelGame"
-
-	log := r.log.With(
-		slog.String("op", op),
-	)
-
-	q, err := r.ch.QueueDeclare(
-		gameDel, // name
-		false,   // durable
-		false,   // delete when unused
-		false,   // exclusive
-		false,   // no-wait
-		nil,     // arguments
-	)
+func (r *RabbitMQ) getLeaderboard(c *call, req getLeaderboardRequest) (getLeaderboardResponse, error) {
+	entries, total, rank, err := r.game.GetLeaderboard(c.ctx, c.user, req.Page, req.PageSize)
 	if err != nil {
-		log.Error("Failed to declare a queue: %v", err)
-		return
+		return getLeaderboardResponse{}, err
 	}
 
-	msgs, err := r.ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
-	if err != nil {
-		log.Error("Failed to register a consumer: %v", err)
-		return
+	resp := getLeaderboardResponse{Entries: make([]leaderboardEntry, 0, len(entries)), Total: total, MyRank: rank}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, leaderboardEntry{
+			Rank:     e.Rank,
+			UserName: e.Login,
+			Rating:   int(math.Round(e.Stat.Rating.Value)),
+			Wins:     e.Stat.Wins,
+			Losses:   e.Stat.Losses,
+		})
 	}
+	c.log.Info("leaderboard sent")
+	return resp, nil
+}
 
-	for d := range msgs {
-		var req gameDelRequest
-		err := json.Unmarshal(d.Body, &req)
-		if err != nil {
-			log.Error("Failed to unmarshal request: %v", err)
-			r.sendResp(d, gameDelResponse{Err: ErrBadRequest.Error()})
-			continue
-		}
-
-		//TODO: process the case of game cancellation
-		_, err = r.game.DelGame(req.UserName)
-		if err != nil {
-			r.sendResp(d, gameDelResponse{Err: ErrInternal.Error()})
-			continue
-		}
-
-		log.With("login", req.UserName).Info("game deleted")
-		r.sendResp(d, gameDelResponse{})
+func toMatchInfo(m game.Match) matchInfo {
+	return matchInfo{
+		ID:                m.ID,
+		Winner:            m.Winner,
+		Loser:             m.Loser,
+		StartedAt:         m.StartedAt,
+		EndedAt:           m.EndedAt,
+		DurationSec:       int(m.Duration().Seconds()),
+		Shots:             m.Shots,
+		WinnerRatingDelta: m.WinnerRatingDelta,
+		LoserRatingDelta:  m.LoserRatingDelta,
+		EndReason:         string(m.EndReason),
 	}
 }
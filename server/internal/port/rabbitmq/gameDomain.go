@@ -1,20 +1,29 @@
 package rabbitmq
 
-type gameCreateRequest struct {
-	UserName string `json:"user_name"`
-}
+import (
+	"errors"
+	"time"
+)
+
+// the user of game requests is taken from the session token, see RabbitMQ.authorize
+
+type gameCreateRequest struct{}
 
 type gameJoinRequest struct {
 	CreatorUserName string `json:"creator_user_name"`
-	JoiningUserName string `json:"joining_user_name"`
 }
 
-type gameDelRequest struct {
-	UserName string `json:"user_name"`
+func (req gameJoinRequest) validate() error {
+	if req.CreatorUserName == "" {
+		return errors.New("empty creator user name")
+	}
+	return nil
 }
 
+type gameDelRequest struct{}
+
 type gameDelResponse struct {
-	Err string `json:"error,omitempty"`
+	reply
 }
 
 type getAvailableGamesRequest struct{}
@@ -24,33 +33,138 @@ type gameResultRequest struct {
 	Loser  string `json:"loser"`
 }
 
+func (req gameResultRequest) validate() error {
+	if req.Winner == "" || req.Loser == "" {
+		return errors.New("empty winner or loser")
+	}
+	return nil
+}
+
 type getStatRequest struct {
 	UserName string `json:"user_name"`
 }
 
+func (req getStatRequest) validate() error {
+	if req.UserName == "" {
+		return errors.New("empty user name")
+	}
+	return nil
+}
+
 type getStatResponse struct {
-	Rating int    `json:"rating"`
-	Wins   int    `json:"wins"`
-	Losses int    `json:"losses"`
-	Err    string `json:"error,omitempty"`
+	Rating int `json:"rating"`
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	reply
 }
 
 type getAvailableGamesResponse struct {
 	Games []string `json:"games"`
-	Err   string   `json:"error,omitempty"`
+	reply
 }
 
 type gameCreateResponse struct {
 	User2 string `json:"user2,omitempty"`
-	Err   string `json:"error,omitempty"`
+	reply
 }
 
 type gameJoinResponse struct {
-	Err string `json:"error,omitempty"`
+	reply
 }
 
 type gameResultResponse struct {
-	Err string `json:"error,omitempty"`
+	reply
+}
+
+type getHistoryRequest struct {
+	UserName string `json:"user_name,omitempty"` // the user from the session token if empty
+	Page     int    `json:"page"`                // starting from 1
+	PageSize int    `json:"page_size"`
+}
+
+type matchInfo struct {
+	ID                int64     `json:"id"`
+	Winner            string    `json:"winner"`
+	Loser             string    `json:"loser"`
+	StartedAt         time.Time `json:"started_at"`
+	EndedAt           time.Time `json:"ended_at"`
+	DurationSec       int       `json:"duration_sec"`
+	Shots             int       `json:"shots"`
+	WinnerRatingDelta float64   `json:"winner_rating_delta"`
+	LoserRatingDelta  float64   `json:"loser_rating_delta"`
+	EndReason         string    `json:"end_reason"`
+}
+
+type getHistoryResponse struct {
+	Matches []matchInfo `json:"matches"`
+	Total   int         `json:"total"`
+	reply
+}
+
+type getLeaderboardRequest struct {
+	Page     int `json:"page"` // starting from 1
+	PageSize int `json:"page_size"`
+}
+
+type leaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	UserName string `json:"user_name"`
+	Rating   int    `json:"rating"`
+	Wins     int    `json:"wins"`
+	Losses   int    `json:"losses"`
+}
+
+type getLeaderboardResponse struct {
+	Entries []leaderboardEntry `json:"entries"`
+	Total   int                `json:"total"`
+	MyRank  int                `json:"my_rank"` // 0 if the user has not played yet
+	reply
+}
+
+// quickMatchRequest puts the user into the quick match queue or takes the user out of it
+type quickMatchRequest struct {
+	Cancel bool `json:"cancel,omitempty"`
+}
+
+// quickMatchResponse is sent once the opponent is found, or at once to a cancel request
+type quickMatchResponse struct {
+	Opponent       string `json:"opponent,omitempty"`
+	OpponentRating int    `json:"opponent_rating,omitempty"`
+	reply
+}
+
+type getReplayRequest struct {
+	MatchID int64 `json:"match_id"`
+}
+
+func (req getReplayRequest) validate() error {
+	if req.MatchID < 1 {
+		return errors.New("match id must be positive")
+	}
+	return nil
+}
+
+// replayEvent structure:
+// fleet { player, fleet };
+// shot { player - the attacker, x, y, hit, destroy };
+// end { player - the winner, reason }
+type replayEvent struct {
+	Seq     int       `json:"seq"`
+	Type    string    `json:"type"`
+	Player  string    `json:"player"`
+	X       int       `json:"x,omitempty"`
+	Y       int       `json:"y,omitempty"`
+	Hit     bool      `json:"hit,omitempty"`
+	Destroy bool      `json:"destroy,omitempty"`
+	Fleet   [][]point `json:"fleet,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+type getReplayResponse struct {
+	Match  matchInfo     `json:"match"`
+	Events []replayEvent `json:"events"`
+	reply
 }
 
 const ( //queue names
@@ -60,4 +174,8 @@ const ( //queue names
 	saveGameResult    = "game.save_result"
 	getUserStat       = "game.get_user_stat"
 	gameDel           = "game.del"
+	getHistory        = "game.get_history"
+	getLeaderboard    = "game.get_leaderboard"
+	quickMatch        = "game.quick_match"
+	getReplay         = "game.get_replay"
 )
@@ -0,0 +1,34 @@
+package rabbitmq
+
+import (
+	"context"
+)
+
+type matchmakingService interface {
+	QuickMatch(ctx context.Context, userName string) error
+	CancelQuickMatch(userName string) error
+}
+
+// quickMatch answers once the opponent is found, see opponentFound, or at once to a cancel request
+func (r *RabbitMQ) quickMatch(c *call, req quickMatchRequest) (quickMatchResponse, error) {
+	if req.Cancel {
+		err := r.game.CancelQuickMatch(c.user)
+		if err != nil {
+			return quickMatchResponse{}, err
+		}
+		r.waiting.take(c.user)
+		c.log.Info("quick match cancelled")
+		return quickMatchResponse{}, nil
+	}
+
+	// the opponent may be found before QuickMatch returns
+	r.waiting.add(c.user, waitingRequest{d: c.d, quick: true})
+	err := r.game.QuickMatch(c.ctx, c.user)
+	if err != nil {
+		r.waiting.take(c.user)
+		return quickMatchResponse{}, err
+	}
+	// the user is waiting for the opponent to be found
+	c.skipReply()
+	return quickMatchResponse{}, nil
+}
@@ -0,0 +1,40 @@
+package rabbitmq
+
+import "log/slog"
+
+// getReplay sends a finished game with the ordered log of its events
+func (r *RabbitMQ) getReplay(c *call, req getReplayRequest) (getReplayResponse, error) {
+	match, err := r.game.GetReplay(c.ctx, req.MatchID)
+	if err != nil {
+		return getReplayResponse{}, err
+	}
+
+	resp := getReplayResponse{
+		Match:  toMatchInfo(match),
+		Events: make([]replayEvent, 0, len(match.Events)),
+	}
+	for _, e := range match.Events {
+		var fleet [][]point
+		for _, ship := range e.Fleet {
+			cells := make([]point, 0, len(ship))
+			for _, p := range ship {
+				cells = append(cells, point{X: p.X, Y: p.Y})
+			}
+			fleet = append(fleet, cells)
+		}
+		resp.Events = append(resp.Events, replayEvent{
+			Seq:     e.Seq,
+			Type:    string(e.Type),
+			Player:  e.Player,
+			X:       e.X,
+			Y:       e.Y,
+			Hit:     e.Hit,
+			Destroy: e.Destroy,
+			Fleet:   fleet,
+			Reason:  string(e.Reason),
+			At:      e.At,
+		})
+	}
+	c.log.Info("replay sent", slog.Int64("match_id", req.MatchID))
+	return resp, nil
+}
@@ -0,0 +1,47 @@
+package rabbitmq
+
+import (
+	"battle-ship_server/internal/service/game"
+	"errors"
+)
+
+// resume lets a player who reconnected continue the running game or give it up
+func (r *RabbitMQ) resume(c *call, req resumeRequest) (resumeResponse, error) {
+	if req.Forfeit {
+		return resumeResponse{}, r.game.LeaveGames(c.ctx, c.user)
+	}
+
+	state, err := r.game.Resume(c.user)
+	if errors.Is(err, game.ErrGameNotFound) {
+		return resumeResponse{}, nil // no opponent, nothing to resume
+	} else if err != nil {
+		return resumeResponse{}, err
+	}
+
+	fleet := make([][]point, 0, len(state.Fleet))
+	for _, ship := range state.Fleet {
+		cells := make([]point, 0, len(ship))
+		for _, p := range ship {
+			cells = append(cells, point{X: p.X, Y: p.Y})
+		}
+		fleet = append(fleet, cells)
+	}
+	c.log.Info("game state sent")
+	return resumeResponse{
+		Opponent:      state.Opponent,
+		FleetPlaced:   state.FleetPlaced,
+		Started:       state.Started,
+		MyTurn:        state.MyTurn,
+		Fleet:         fleet,
+		MyShots:       shotInfos(state.MyShots),
+		OpponentShots: shotInfos(state.OpponentShots),
+	}, nil
+}
+
+func shotInfos(shots []game.Shot) []shotInfo {
+	infos := make([]shotInfo, 0, len(shots))
+	for _, s := range shots {
+		infos = append(infos, shotInfo{X: s.X, Y: s.Y, Hit: s.Hit, Destroy: s.Destroy})
+	}
+	return infos
+}
@@ -0,0 +1,356 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+// rpcStats counts the requests of every queue: requests, errors, panics, retried, dead_lettered,
+// requeued - returned to the broker unanswered, and busy_us - the time spent serving them.
+// It is published by expvar under the name "rpc", served by the metrics address of the config.
+var rpcStats = expvar.NewMap("rpc")
+
+// endpoint describes how the requests of a queue are served
+type endpoint struct {
+	queue string
+	// noAuth endpoints are served without checking the session token,
+	// the handler authorizes the request itself if it needs to
+	noAuth bool
+	// errors of the services shown to the client as they are, the other errors are reported as ErrInternal.
+	// ErrBadRequest and ErrUnauthorized are always shown.
+	errors []error
+	// ordered endpoints handle the requests one at a time in the order they come, whatever the consumer settings
+	ordered bool
+}
+
+// Consumers sets how the requests of every queue are consumed
+type Consumers struct {
+	Default   Consumer
+	Endpoints map[string]Consumer // queue name -> settings of the queue, zero fields are taken from Default
+	// MaxRetries is how many times a request failed by a transient error is tried again before it is dead-lettered
+	MaxRetries int
+}
+
+type Consumer struct {
+	Workers  int // requests handled at once
+	Prefetch int // requests the broker hands over before they are acknowledged, at least Workers
+}
+
+// of returns the settings of the queue
+func (c Consumers) of(queue string) Consumer {
+	consumer := c.Endpoints[queue]
+	if consumer.Workers == 0 {
+		consumer.Workers = c.Default.Workers
+	}
+	if consumer.Prefetch == 0 {
+		consumer.Prefetch = c.Default.Prefetch
+	}
+	consumer.Workers = max(consumer.Workers, 1)
+	consumer.Prefetch = max(consumer.Prefetch, consumer.Workers)
+	return consumer
+}
+
+var (
+	// errPanicked replaces the error of a handler that panicked
+	errPanicked = errors.New("handler panicked")
+	// errMalformed is the error of a request which can't be decoded
+	errMalformed = errors.New("malformed body")
+)
+
+// call is a request being served
+type call struct {
+	ctx  context.Context
+	ch   *amqp.Channel // the channel of the consumer
+	d    amqp.Delivery
+	user string // the login from the session token, empty for the noAuth endpoints
+	log  *slog.Logger
+	skip bool
+}
+
+// skipReply tells that the handler has nothing to send back or that the response is sent later,
+// e.g. once the opponent is found. Errors are not sent either, they are only logged.
+func (c *call) skipReply() {
+	c.skip = true
+}
+
+// validator is implemented by the requests which can be checked before they are handled
+type validator interface {
+	validate() error
+}
+
+// reply carries the error of a response, it is embedded into every response
+type reply struct {
+	Err string `json:"error,omitempty"`
+}
+
+func (r *reply) fail(err string) {
+	r.Err = err
+}
+
+// response is a pointer to a response with an embedded reply
+type response[T any] interface {
+	*T
+	fail(err string)
+}
+
+// handler serves a decoded and authorized request. The response returned with an error
+// is sent with the error set, so a handler may fill the fields the client needs along with the error.
+type handler[Req, Resp any] func(c *call, req Req) (Resp, error)
+
+// serve consumes the requests of the endpoint with a pool of workers until the connection to the broker is lost.
+// The consumer has a channel of its own, it is reopened if the broker closes it.
+func serve[Req, Resp any, PResp response[Resp]](r *RabbitMQ, e endpoint, handle handler[Req, Resp]) {
+	const op = "RabbitMQ.serve"
+
+	log := r.log.With(
+		slog.String("op", op),
+		slog.String("queue", e.queue),
+	)
+
+	consumer := r.consumers.of(e.queue)
+	if e.ordered {
+		consumer.Workers = 1
+	}
+	stats := queueStats(e.queue)
+	conn := r.connection()
+
+	go func() {
+		delay := minReconnectDelay
+		for {
+			if listen(conn, e, consumer, log, func(ch *amqp.Channel, d amqp.Delivery) {
+				dispatch[Req, Resp, PResp](r, e, stats, ch, d, handle)
+			}) {
+				delay = minReconnectDelay
+			}
+			if conn.IsClosed() || r.ctx.Err() != nil {
+				return // supervise restarts the consumers on the new connection
+			}
+
+			log.Warn("The channel of the consumer is closed, reopening", slog.Duration("retry_in", delay))
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			delay = min(2*delay, maxReconnectDelay)
+		}
+	}()
+}
+
+// listen opens a channel, declares the queue of the endpoint and hands its requests to the workers
+// until the channel is closed. It returns false if the consumer could not be started.
+func listen(conn *amqp.Connection, e endpoint, consumer Consumer, log *slog.Logger, handle func(ch *amqp.Channel, d amqp.Delivery)) bool {
+	ch, err := conn.Channel()
+	if err != nil {
+		log.Error("Failed to open a channel", slog.String("error", err.Error()))
+		return false
+	}
+	defer ch.Close()
+
+	err = ch.Qos(
+		consumer.Prefetch, // prefetch count
+		0,                 // prefetch size
+		false,             // global
+	)
+	if err != nil {
+		log.Error("Failed to set the prefetch", slog.String("error", err.Error()))
+		return false
+	}
+
+	_, err = declareDeadLetters(ch, e.queue)
+	if err != nil {
+		log.Error("Failed to declare the dead letters", slog.String("error", err.Error()))
+		return false
+	}
+
+	err = declareRequestQueue(conn, e.queue, log)
+	if err != nil {
+		log.Error("Failed to declare a queue", slog.String("error", err.Error()))
+		return false
+	}
+
+	msgs, err := ch.Consume(
+		e.queue, // queue
+		"",      // consumer
+		false,   // auto-ack
+		false,   // exclusive
+		false,   // no-local
+		false,   // no-wait
+		nil,     // args
+	)
+	if err != nil {
+		log.Error("Failed to register a consumer", slog.String("error", err.Error()))
+		return false
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < consumer.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := range msgs {
+				handle(ch, d)
+			}
+		}()
+	}
+	wg.Wait()
+	return true
+}
+
+// dispatch serves a single request, sends the response unless the handler skipped it and acknowledges the request.
+// A request failed by a transient error is tried again up to MaxRetries times, the response is not sent then.
+// The malformed requests, the requests the handler panicked on and the ones out of retries are answered
+// and dead-lettered for the inspection.
+func dispatch[Req, Resp any, PResp response[Resp]](r *RabbitMQ, e endpoint, stats *expvar.Map, ch *amqp.Channel, d amqp.Delivery, handle handler[Req, Resp]) {
+	c := &call{
+		ctx: r.ctx,
+		ch:  ch,
+		d:   d,
+		log: r.log.With(slog.String("queue", e.queue)),
+	}
+	start := time.Now()
+	defer func() {
+		took := time.Since(start)
+		stats.Add("requests", 1)
+		stats.Add("busy_us", took.Microseconds())
+		c.log.Debug("Request served", slog.Duration("took", took))
+	}()
+
+	deadLetter := false
+	resp, err := invoke(r, e, stats, c, handle)
+	if err != nil {
+		if r.ctx.Err() != nil {
+			// the server is stopping, another one or this one after the restart will take the request
+			stats.Add("requeued", 1)
+			nack(c, true)
+			return
+		}
+		if transient(err) && retries(d) < r.consumers.MaxRetries {
+			stats.Add("retried", 1)
+			c.log.Warn("Request failed, retrying", slog.String("error", err.Error()), slog.Int("retries", retries(d)))
+			if err := r.retry(c, e.queue); err != nil {
+				c.log.Error("Failed to retry a request", slog.String("error", err.Error()))
+				stats.Add("requeued", 1)
+				nack(c, true)
+				return
+			}
+			ack(c)
+			return
+		}
+
+		stats.Add("errors", 1)
+		shown := e.expose(err)
+		if shown == ErrInternal {
+			c.log.Error("Request failed", slog.String("error", err.Error()))
+		} else {
+			c.log.Info("Request rejected", slog.String("error", err.Error()))
+		}
+		PResp(&resp).fail(shown.Error())
+		deadLetter = errors.Is(err, errMalformed) || errors.Is(err, errPanicked) || transient(err)
+	}
+
+	if !c.skip {
+		err = r.publishResp(ch, d, resp)
+		if err != nil {
+			stats.Add("requeued", 1)
+			nack(c, true) // the broker will deliver it again once the channel is back
+			return
+		}
+	}
+	if deadLetter {
+		stats.Add("dead_lettered", 1)
+		nack(c, false)
+		return
+	}
+	ack(c)
+}
+
+func ack(c *call) {
+	if err := c.d.Ack(false); err != nil {
+		c.log.Error("Failed to acknowledge a request", slog.String("error", err.Error()))
+	}
+}
+
+// nack returns the request to the broker, to the queue if requeue is set or to the dead letter queue otherwise
+func nack(c *call, requeue bool) {
+	if err := c.d.Nack(false, requeue); err != nil {
+		c.log.Error("Failed to reject a request", slog.String("error", err.Error()))
+	}
+}
+
+// transient tells if another attempt of the request may succeed, e.g. the query timed out or the server is stopping
+func transient(err error) bool {
+	var temporary interface{ Temporary() bool }
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, context.Canceled) ||
+		errors.As(err, &temporary) && temporary.Temporary()
+}
+
+// invoke decodes, authorizes and validates the request and passes it to the handler.
+// A panic of the handler is recovered and reported as ErrInternal.
+func invoke[Req, Resp any](r *RabbitMQ, e endpoint, stats *expvar.Map, c *call, handle handler[Req, Resp]) (resp Resp, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			stats.Add("panics", 1)
+			c.log.Error("Handler panicked", slog.String("panic", fmt.Sprint(p)), slog.String("stack", string(debug.Stack())))
+			var zero Resp
+			resp, err = zero, errPanicked
+		}
+	}()
+
+	var req Req
+	if err := json.Unmarshal(c.d.Body, &req); err != nil {
+		return resp, fmt.Errorf("%w: %w: %s", ErrBadRequest, errMalformed, err)
+	}
+
+	if !e.noAuth {
+		c.user, err = r.authorize(c.d)
+		if err != nil {
+			return resp, err
+		}
+		c.log = c.log.With(slog.String("login", c.user))
+	}
+
+	if v, ok := any(req).(validator); ok {
+		if err := v.validate(); err != nil {
+			return resp, fmt.Errorf("%w: %s", ErrBadRequest, err)
+		}
+	}
+
+	return handle(c, req)
+}
+
+// expose returns the error to show to the client
+func (e endpoint) expose(err error) error {
+	switch {
+	case errors.Is(err, ErrBadRequest):
+		return ErrBadRequest
+	case errors.Is(err, ErrUnauthorized):
+		return ErrUnauthorized
+	}
+	for _, shown := range e.errors {
+		if errors.Is(err, shown) {
+			return shown
+		}
+	}
+	return ErrInternal
+}
+
+// queueStats returns the counters of the queue, they survive the reconnects to the broker
+func queueStats(queue string) *expvar.Map {
+	if stats, ok := rpcStats.Get(queue).(*expvar.Map); ok {
+		return stats
+	}
+	stats := new(expvar.Map).Init()
+	rpcStats.Set(queue, stats)
+	return stats
+}
@@ -1,53 +1,225 @@
 package rabbitmq
 
 import (
+	"battle-ship_server/internal/service/auth"
+	"battle-ship_server/internal/service/game"
+	"battle-ship_server/internal/storage"
+	"context"
 	"encoding/json"
 	"errors"
 	"log/slog"
+	"sync"
+	"time"
 
 	"github.com/streadway/amqp"
 )
 
 var (
-	ErrBadRequest = errors.New("bad request")
-	ErrInternal   = errors.New("internal error")
+	ErrBadRequest   = errors.New("bad request")
+	ErrInternal     = errors.New("internal error")
+	ErrUnauthorized = errors.New("unauthorized")
 )
 
+// tokenHeader is the message header with the session token issued at login
+const tokenHeader = "token"
+
 type RabbitMQ struct {
+	url  string
+	mu   sync.RWMutex // guards conn and ch, they are replaced on reconnect
 	conn *amqp.Connection
 	ch   *amqp.Channel
 	log  *slog.Logger
 
+	// ctx is passed to the services for the requests, it is cancelled on Close to abort the storage calls in flight
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	consumers Consumers
+	waiting   *waiting
+
 	auth authService
 	game gameService
 }
 
-func New(urlRmq string, log *slog.Logger, auth authService, game gameService) *RabbitMQ {
-	conn, err := amqp.Dial(urlRmq)
-	if err != nil {
-		panic(err)
+// Delays between the attempts to connect to the broker, doubled after every failed attempt
+const (
+	minReconnectDelay = 500 * time.Millisecond
+	maxReconnectDelay = 30 * time.Second
+)
+
+// New connects to the broker, retrying until it is reachable
+func New(urlRmq string, log *slog.Logger, consumers Consumers, auth authService, game gameService) *RabbitMQ {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &RabbitMQ{url: urlRmq, log: log, ctx: ctx, cancel: cancel, consumers: consumers, waiting: newWaiting(), auth: auth, game: game}
+	r.connect()
+	return r
+}
+
+// Run starts serving the requests. The consumers are restarted whenever the connection to the broker is restored.
+func (r *RabbitMQ) Run() {
+	r.consume()
+	go r.supervise()
+
+	// it reads the channel of the game service, not the broker, so it is not restarted
+	go r.GameEvents()
+}
+
+// requestQueues are the queues of the requests served by the server, each of them has a dead letter queue
+var requestQueues = []string{
+	authLogin, authRegister, authLogout,
+	gameCreate, gameDel, getAvailableGames, gameJoin, quickMatch, saveGameResult,
+	getUserStat, getHistory, getLeaderboard, getReplay,
+	gameBattle, gameResume, listLive, gameWatch,
+}
+
+// consume declares the exchanges and starts the consumers of all request queues.
+// The consumers stop when the connection is lost.
+func (r *RabbitMQ) consume() {
+	r.declareExchanges()
+
+	serve(r, endpoint{queue: authLogin, noAuth: true, errors: []error{auth.ErrWrongPass, storage.ErrUserNotFound}}, r.login)
+	serve(r, endpoint{queue: authRegister, noAuth: true, errors: []error{storage.ErrUserExists}}, r.register)
+	serve(r, endpoint{queue: authLogout, noAuth: true}, r.logout)
+
+	serve(r, endpoint{queue: gameCreate, errors: []error{game.ErrAlreadyPlaying}}, r.createGame)
+	serve(r, endpoint{queue: gameDel, errors: []error{game.ErrBattleStarted}}, r.delGame)
+	serve(r, endpoint{queue: getAvailableGames}, r.getAvailableGames)
+	serve(r, endpoint{queue: gameJoin, errors: []error{game.ErrGameNotFound, game.ErrSelfPlay, game.ErrAlreadyPlaying}}, r.joinGame)
+	serve(r, endpoint{queue: quickMatch, errors: []error{game.ErrAlreadyPlaying, game.ErrNotQueued}}, r.quickMatch)
+	serve(r, endpoint{queue: saveGameResult, errors: []error{
+		game.ErrGameNotFound,
+		game.ErrNotStarted,
+		game.ErrNotParticipant,
+		game.ErrNotFinished,
+		game.ErrWrongResult,
+	}}, r.gameResult)
+	serve(r, endpoint{queue: getUserStat}, r.getUserStat)
+	serve(r, endpoint{queue: getHistory}, r.getHistory)
+	serve(r, endpoint{queue: getLeaderboard}, r.getLeaderboard)
+	serve(r, endpoint{queue: getReplay, errors: []error{game.ErrMatchNotFound}}, r.getReplay)
+
+	serve(r, endpoint{queue: gameBattle, noAuth: true, ordered: true, errors: []error{
+		game.ErrGameNotFound,
+		game.ErrNotYourTurn,
+		game.ErrFleetPlaced,
+		game.ErrNotStarted,
+		game.ErrBadFleet,
+		game.ErrBadShot,
+		game.ErrAlreadyShot,
+		game.ErrChatEmpty,
+		game.ErrChatTooLong,
+		game.ErrChatTooFast,
+	}}, r.battle)
+	serve(r, endpoint{queue: gameResume}, r.resume)
+	serve(r, endpoint{queue: listLive}, r.listLiveGames)
+	serve(r, endpoint{queue: gameWatch, errors: []error{game.ErrGameNotFound}}, r.watch)
+}
+
+// supervise reconnects to the broker and restarts the consumers every time the connection is lost until Close
+func (r *RabbitMQ) supervise() {
+	const op = "RabbitMQ.supervise"
+
+	log := r.log.With(
+		slog.String("op", op),
+	)
+
+	for {
+		r.mu.RLock()
+		closed := r.conn.NotifyClose(make(chan *amqp.Error, 1))
+		r.mu.RUnlock()
+
+		select {
+		case <-r.ctx.Done():
+			return
+		case err := <-closed:
+			if r.ctx.Err() != nil {
+				return
+			}
+			reason := "closed"
+			if err != nil {
+				reason = err.Error()
+			}
+			log.Error("Lost the connection to the broker, reconnecting", slog.String("reason", reason))
+		}
+
+		if !r.connect() {
+			return
+		}
+		r.consume()
+		log.Info("Reconnected to the broker, consumers restarted")
 	}
+}
 
-	ch, err := conn.Channel()
-	if err != nil {
-		panic(err)
+// connect dials the broker with a growing delay between the attempts.
+// It returns false if the server is closed before the connection is made.
+func (r *RabbitMQ) connect() bool {
+	const op = "RabbitMQ.connect"
+
+	log := r.log.With(
+		slog.String("op", op),
+	)
+
+	delay := minReconnectDelay
+	for {
+		conn, err := amqp.Dial(r.url)
+		if err == nil {
+			var ch *amqp.Channel
+			ch, err = conn.Channel()
+			if err == nil {
+				r.mu.Lock()
+				r.conn, r.ch = conn, ch
+				r.mu.Unlock()
+				return true
+			}
+			conn.Close()
+		}
+
+		log.Warn("Failed to connect to the broker", slog.String("error", err.Error()), slog.Duration("retry_in", delay))
+		select {
+		case <-r.ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxReconnectDelay)
 	}
-	return &RabbitMQ{conn: conn, ch: ch, log: log, auth: auth, game: game}
 }
 
-func (r *RabbitMQ) Run() {
-	go r.Login()
-	go r.Register()
-	go r.CreateGame()
-	go r.DelGame()
-	go r.GetAvailableGames()
-	go r.JoinGame()
-	go r.GameResult()
-	go r.GetUserStat()
+// connection returns the current connection to the broker
+func (r *RabbitMQ) connection() *amqp.Connection {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.conn
 }
 
+// channel returns the shared channel of the current connection, the consumers have channels of their own
+func (r *RabbitMQ) channel() *amqp.Channel {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.ch
+}
+
+// authorize returns the login of the user whose session token is attached to the message
+func (r *RabbitMQ) authorize(d amqp.Delivery) (string, error) {
+	token, ok := d.Headers[tokenHeader].(string)
+	if !ok || token == "" {
+		return "", ErrUnauthorized
+	}
+	login, err := r.auth.ValidateToken(token)
+	if err != nil {
+		r.log.Info("Rejected session token", slog.String("reason", err.Error()))
+		return "", ErrUnauthorized
+	}
+	return login, nil
+}
+
+// sendResp publishes the response on the shared channel
 func (r *RabbitMQ) sendResp(d amqp.Delivery, response any) {
-	const op = "RabbitMQ.sendResp"
+	_ = r.publishResp(r.channel(), d, response)
+}
+
+// publishResp publishes the response to the reply queue of the request
+func (r *RabbitMQ) publishResp(ch *amqp.Channel, d amqp.Delivery, response any) error {
+	const op = "RabbitMQ.publishResp"
 
 	log := r.log.With(
 		slog.String("op", op),
@@ -55,11 +227,11 @@ func (r *RabbitMQ) sendResp(d amqp.Delivery, response any) {
 
 	body, err := json.Marshal(response)
 	if err != nil {
-		log.Error("Failed to marshal response: %v", err)
-		return
+		log.Error("Failed to marshal response", slog.String("error", err.Error()))
+		return err
 	}
 
-	err = r.ch.Publish(
+	err = ch.Publish(
 		"",        // exchange
 		d.ReplyTo, // routing key
 		false,     // mandatory
@@ -70,13 +242,15 @@ func (r *RabbitMQ) sendResp(d amqp.Delivery, response any) {
 			Body:          body,
 		})
 	if err != nil {
-		log.Error("Failed to publish response: %v", err)
-		return
+		log.Error("Failed to publish response", slog.String("error", err.Error()))
+		return err
 	}
+	return nil
 }
 
 func (r *RabbitMQ) Close() error {
-	if err := r.ch.Close(); err != nil {
+	r.cancel()
+	if err := r.channel().Close(); err != nil {
 		return err
 	}
 	if err := r.conn.Close(); err != nil {
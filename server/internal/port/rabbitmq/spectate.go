@@ -0,0 +1,128 @@
+package rabbitmq
+
+import (
+	"battle-ship_server/internal/service/game"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/streadway/amqp"
+)
+
+type spectateService interface {
+	LiveGames() []game.LiveGame
+	Watch(gameID string) (game.SpectatorView, error)
+}
+
+func (r *RabbitMQ) listLiveGames(_ *call, _ listLiveRequest) (listLiveResponse, error) {
+	live := r.game.LiveGames()
+	games := make([]liveGame, 0, len(live))
+	for _, g := range live {
+		games = append(games, toLiveGame(g))
+	}
+	return listLiveResponse{Games: games}, nil
+}
+
+func (r *RabbitMQ) watch(c *call, req watchRequest) (watchResponse, error) {
+	view, err := r.game.Watch(req.GameID)
+	if err != nil {
+		return watchResponse{}, err
+	}
+
+	shots := make(map[string][]shotInfo, len(view.SeaShots))
+	for user, s := range view.SeaShots {
+		shots[user] = shotInfos(s)
+	}
+	c.log.Info("spectator joined", slog.String("game_id", req.GameID))
+	return watchResponse{
+		Game:  toLiveGame(view.LiveGame),
+		Turn:  view.Turn,
+		Shots: shots,
+	}, nil
+}
+
+func toLiveGame(g game.LiveGame) liveGame {
+	return liveGame{
+		ID:        g.ID,
+		User1:     g.User1,
+		User2:     g.User2,
+		Started:   g.Started,
+		Shots:     g.Shots,
+		StartedAt: g.StartedAt,
+	}
+}
+
+// sendToSpectators publishes the event to the spectate exchange with the id of the game as the routing key
+func (r *RabbitMQ) sendToSpectators(e game.Event) {
+	const op = "RabbitMQ.sendToSpectators"
+
+	log := r.log.With(
+		slog.String("op", op),
+		slog.String("game_id", e.GameID),
+	)
+
+	msg := spectatorMessage{
+		GameID:   e.GameID,
+		Attacker: e.Attacker,
+		X:        e.X,
+		Y:        e.Y,
+		Hit:      e.Hit,
+		Destroy:  e.Destroy,
+		Winner:   e.Winner,
+		Reason:   string(e.Reason),
+	}
+	switch e.Type {
+	case game.EventReady:
+		msg.Type = ready
+	case game.EventShot:
+		msg.Type = result
+	case game.EventEnd:
+		msg.Type = end
+		msg.Fleets = make(map[string][][]point, len(e.Fleets))
+		for user, fleet := range e.Fleets {
+			ships := make([][]point, 0, len(fleet))
+			for _, ship := range fleet {
+				cells := make([]point, 0, len(ship))
+				for _, p := range ship {
+					cells = append(cells, point{X: p.X, Y: p.Y})
+				}
+				ships = append(ships, cells)
+			}
+			msg.Fleets[user] = ships
+		}
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		log.Error("Failed to marshal message", slog.String("error", err.Error()))
+		return
+	}
+
+	err = r.channel().Publish(
+		spectateExchange, // exchange
+		e.GameID,         // routing key
+		false,            // mandatory
+		false,            // immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		})
+	if err != nil {
+		log.Error("Failed to publish message", slog.String("error", err.Error()))
+	}
+}
+
+// declareExchanges declares the exchange the game events are published to for the spectators
+func (r *RabbitMQ) declareExchanges() {
+	err := r.channel().ExchangeDeclare(
+		spectateExchange, // name
+		"topic",          // type
+		false,            // durable
+		false,            // auto-deleted
+		false,            // internal
+		false,            // no-wait
+		nil,              // arguments
+	)
+	if err != nil {
+		r.log.Error("Failed to declare an exchange", slog.String("error", err.Error()))
+	}
+}
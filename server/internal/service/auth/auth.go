@@ -2,8 +2,11 @@ package auth
 
 import (
 	"battle-ship_server/internal/storage"
+	"context"
 	"errors"
 	"log/slog"
+	"sync"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -13,20 +16,32 @@ var (
 )
 
 type UserStorage interface {
-	SaveUser(login string, password []byte) error
-	GetUserData(login string) ([]byte, error)
+	SaveUser(ctx context.Context, login string, password []byte) error
+	GetUserData(ctx context.Context, login string) ([]byte, error)
 }
 
 type Service struct {
 	Storage UserStorage
 	log     *slog.Logger
+
+	tokenSecret []byte
+	tokenTTL    time.Duration
+	sessions    map[string]session // session id -> session
+	mu          sync.RWMutex
 }
 
-func New(Storage UserStorage, log *slog.Logger) *Service {
-	return &Service{Storage: Storage, log: log}
+func New(Storage UserStorage, log *slog.Logger, tokenSecret string, tokenTTL time.Duration) *Service {
+	return &Service{
+		Storage:     Storage,
+		log:         log,
+		tokenSecret: []byte(tokenSecret),
+		tokenTTL:    tokenTTL,
+		sessions:    make(map[string]session),
+	}
 }
 
-func (s *Service) Register(login string, password string) error {
+// Register creates the user and returns a session token
+func (s *Service) Register(ctx context.Context, login string, password string) (token string, err error) {
 	const op = "Service.Register"
 
 	log := s.log.With(
@@ -37,23 +52,30 @@ func (s *Service) Register(login string, password string) error {
 	passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		log.Error(err.Error())
-		return err
+		return "", err
 	}
 
-	err = s.Storage.SaveUser(login, passHash)
+	err = s.Storage.SaveUser(ctx, login, passHash)
 	if errors.Is(err, storage.ErrUserExists) {
 		log.Info("user already exists")
-		return err
+		return "", err
 	} else if err != nil {
 		log.Error(err.Error())
-		return err
+		return "", err
+	}
+
+	token, err = s.issueToken(login)
+	if err != nil {
+		log.Error(err.Error())
+		return "", err
 	}
 
 	log.Info("user registered")
-	return nil
+	return token, nil
 }
 
-func (s *Service) Login(login string, password string) error {
+// Login checks the password and returns a session token
+func (s *Service) Login(ctx context.Context, login string, password string) (token string, err error) {
 	const op = "Service.Login"
 
 	log := s.log.With(
@@ -61,29 +83,48 @@ func (s *Service) Login(login string, password string) error {
 		slog.String("login", login),
 	)
 
-	passHash, err := s.Storage.GetUserData(login)
+	passHash, err := s.Storage.GetUserData(ctx, login)
 	if errors.Is(err, storage.ErrUserNotFound) {
 		log.Info("user not found")
-		return err
+		return "", err
 	} else if err != nil {
 		log.Error(err.Error())
-		return err
+		return "", err
 	}
 
 	err = bcrypt.CompareHashAndPassword(passHash, []byte(password))
 	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
 		log.Info("wrong password")
-		return ErrWrongPass
+		return "", ErrWrongPass
 	} else if err != nil {
 		log.Error(err.Error())
-		return err
+		return "", err
+	}
+
+	token, err = s.issueToken(login)
+	if err != nil {
+		log.Error(err.Error())
+		return "", err
 	}
 
 	log.Info("user logged in")
-	return nil
+	return token, nil
 }
 
-func (s *Service) Logout(login string) error {
-	// nothing to do here
-	return nil
+// Logout revokes the session of the token and returns the login of its user
+func (s *Service) Logout(token string) (login string, err error) {
+	const op = "Service.Logout"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	login, err = s.revoke(token)
+	if err != nil {
+		log.Info("logout with invalid token")
+		return "", err
+	}
+
+	log.Info("user logged out", slog.String("login", login))
+	return login, nil
 }
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid or expired session token")
+)
+
+// claims is the payload of a session token
+type claims struct {
+	Login   string `json:"login"`
+	Session string `json:"sid"`
+	Expires int64  `json:"exp"` // unix seconds
+}
+
+type session struct {
+	login   string
+	expires int64
+}
+
+// issueToken starts a new session and returns its token of the form base64(claims).base64(hmac-sha256(claims))
+func (s *Service) issueToken(login string) (string, error) {
+	c := claims{
+		Login:   login,
+		Session: uuid.NewString(),
+		Expires: time.Now().Add(s.tokenTTL).Unix(),
+	}
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	now := time.Now().Unix()
+	for id, ses := range s.sessions {
+		if now >= ses.expires {
+			delete(s.sessions, id)
+		}
+	}
+	s.sessions[c.Session] = session{login: c.Login, expires: c.Expires}
+	s.mu.Unlock()
+
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(s.sign(payload)), nil
+}
+
+func (s *Service) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.tokenSecret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// parseToken checks the signature and the expiration time of the token
+func (s *Service) parseToken(token string) (claims, error) {
+	enc := base64.RawURLEncoding
+
+	payloadPart, sigPart, ok := strings.Cut(token, ".")
+	if !ok {
+		return claims{}, ErrInvalidToken
+	}
+	payload, err := enc.DecodeString(payloadPart)
+	if err != nil {
+		return claims{}, ErrInvalidToken
+	}
+	sig, err := enc.DecodeString(sigPart)
+	if err != nil {
+		return claims{}, ErrInvalidToken
+	}
+	if !hmac.Equal(sig, s.sign(payload)) {
+		return claims{}, ErrInvalidToken
+	}
+
+	var c claims
+	err = json.Unmarshal(payload, &c)
+	if err != nil {
+		return claims{}, ErrInvalidToken
+	}
+	if time.Now().Unix() >= c.Expires {
+		return claims{}, ErrInvalidToken
+	}
+	return c, nil
+}
+
+// ValidateToken returns the login of the user the token was issued to if its session is still active
+func (s *Service) ValidateToken(token string) (login string, err error) {
+	c, err := s.parseToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.sessions[c.Session]; !ok {
+		return "", ErrInvalidToken
+	}
+	return c.Login, nil
+}
+
+// revoke ends the session of the token
+func (s *Service) revoke(token string) (login string, err error) {
+	c, err := s.parseToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sessions[c.Session]; !ok {
+		return "", ErrInvalidToken
+	}
+	delete(s.sessions, c.Session)
+	return c.Login, nil
+}
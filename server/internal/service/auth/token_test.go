@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newService() *Service {
+	return New(nil, slog.New(slog.NewTextHandler(io.Discard, nil)), "secret", time.Hour)
+}
+
+// signed returns the token of the claims signed by s, the session of the token is not started
+func signed(s *Service, c claims) string {
+	payload, _ := json.Marshal(c)
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(s.sign(payload))
+}
+
+func TestValidateToken(t *testing.T) {
+	enc := base64.RawURLEncoding
+
+	tests := []struct {
+		name string
+		// token returns the token to validate, made by the service that validates it
+		token     func(t *testing.T, s *Service) string
+		wantLogin string
+		wantErr   error
+	}{
+		{
+			name:      "valid",
+			token:     issue("alice"),
+			wantLogin: "alice",
+		},
+		{
+			name:    "empty",
+			token:   func(*testing.T, *Service) string { return "" },
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "no signature",
+			token: func(t *testing.T, s *Service) string {
+				payload, _, _ := strings.Cut(issue("alice")(t, s), ".")
+				return payload
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "not base64",
+			token: func(t *testing.T, s *Service) string {
+				_, sig, _ := strings.Cut(issue("alice")(t, s), ".")
+				return "!!!." + sig
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "tampered login",
+			token: func(t *testing.T, s *Service) string {
+				payload, sig, _ := strings.Cut(issue("alice")(t, s), ".")
+				var c claims
+				b, _ := enc.DecodeString(payload)
+				_ = json.Unmarshal(b, &c)
+				c.Login = "mallory"
+				b, _ = json.Marshal(c)
+				return enc.EncodeToString(b) + "." + sig
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "tampered signature",
+			token: func(t *testing.T, s *Service) string {
+				payload, sig, _ := strings.Cut(issue("alice")(t, s), ".")
+				b, _ := enc.DecodeString(sig)
+				b[0] ^= 1
+				return payload + "." + enc.EncodeToString(b)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "signed with another secret",
+			token: func(t *testing.T, s *Service) string {
+				other := New(nil, s.log, "another secret", time.Hour)
+				return issue("alice")(t, other)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T, s *Service) string {
+				c := claims{Login: "alice", Session: "expired", Expires: time.Now().Add(-time.Second).Unix()}
+				s.sessions[c.Session] = session{login: c.Login, expires: c.Expires}
+				return signed(s, c)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "unknown session",
+			token: func(t *testing.T, s *Service) string {
+				return signed(s, claims{Login: "alice", Session: "unknown", Expires: time.Now().Add(time.Hour).Unix()})
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "revoked",
+			token: func(t *testing.T, s *Service) string {
+				token := issue("alice")(t, s)
+				if _, err := s.Logout(token); err != nil {
+					t.Fatalf("Logout: %v", err)
+				}
+				return token
+			},
+			wantErr: ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newService()
+			login, err := s.ValidateToken(tt.token(t, s))
+			if !errors.Is(err, tt.wantErr) || login != tt.wantLogin {
+				t.Errorf("ValidateToken = %q, %v, want %q, %v", login, err, tt.wantLogin, tt.wantErr)
+			}
+		})
+	}
+}
+
+// issue returns the token of a new session of the user
+func issue(login string) func(t *testing.T, s *Service) string {
+	return func(t *testing.T, s *Service) string {
+		t.Helper()
+		token, err := s.issueToken(login)
+		if err != nil {
+			t.Fatalf("issueToken: %v", err)
+		}
+		return token
+	}
+}
+
+func TestLogout(t *testing.T) {
+	s := newService()
+	alice, err := s.issueToken("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := s.issueToken("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	login, err := s.Logout(alice)
+	if err != nil || login != "alice" {
+		t.Fatalf("Logout = %q, %v, want alice", login, err)
+	}
+	if _, err := s.Logout(alice); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("second Logout = %v, want %v", err, ErrInvalidToken)
+	}
+	// the other sessions go on
+	if login, err := s.ValidateToken(bob); err != nil || login != "bob" {
+		t.Errorf("ValidateToken of another session = %q, %v, want bob", login, err)
+	}
+}
+
+func TestExpiredSessionsForgotten(t *testing.T) {
+	s := newService()
+	s.sessions["expired"] = session{login: "alice", expires: time.Now().Add(-time.Second).Unix()}
+
+	_, err := s.issueToken("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.sessions["expired"]; ok {
+		t.Error("the expired session is kept")
+	}
+}
@@ -0,0 +1,241 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"time"
+)
+
+var (
+	ErrGameNotFound   = errors.New("game not found")
+	ErrNotYourTurn    = errors.New("not your turn")
+	ErrFleetPlaced    = errors.New("fleet already placed")
+	ErrNotStarted     = errors.New("battle not started")
+	ErrNotFinished    = errors.New("game not finished")
+	ErrNotParticipant = errors.New("you are not a player of this game")
+	ErrWrongResult    = errors.New("result does not match the game")
+)
+
+type EventType int
+
+const (
+	EventReady   EventType = iota // both fleets are placed, the battle begins
+	EventShot                     // a shot was resolved
+	EventEnd                      // the battle is over
+	EventChat                     // the opponent sent a chat message
+	EventStarted                  // the opponent joined the game or was found by the quick match, the fleets may be placed
+)
+
+type EndReason string
+
+const (
+	EndAllSunk   EndReason = "all_ships_sunk"
+	EndForfeit   EndReason = "forfeit"      // the loser left the game
+	EndAbandoned EndReason = "abandoned"    // the loser stopped sending heartbeats
+	EndTimeout   EndReason = "turn_timeout" // the loser did not place the fleet or shoot in time
+	EndCancelled EndReason = "cancelled"    // neither player placed the fleet in time, nobody won
+)
+
+// Event is pushed by the service to a player outside of any request.
+// An event with no recipient is for the spectators of the game.
+type Event struct {
+	Type     EventType
+	To       string // login of the player to notify, empty for the spectators
+	GameID   string
+	First    bool   // EventReady: the player shoots first
+	Attacker string // EventShot, EventEnd
+	X        int
+	Y        int
+	Hit      bool
+	Destroy  bool
+	Winner   string               // EventEnd
+	Reason   EndReason            // EventEnd
+	Fleets   map[string][][]Point // EventEnd for the spectators: the ships of the players revealed
+	From     string               // EventChat
+	Text     string               // EventChat
+
+	Opponent       string  // EventStarted
+	OpponentRating float64 // EventStarted of the quick match
+}
+
+// Events returns the channel of events that must be delivered to players
+func (s *Service) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Service) notify(events ...Event) {
+	for _, e := range events {
+		s.events <- e
+	}
+}
+
+// gameOf returns the running game in which the user takes part. Must be called with s.mu held.
+func (s *Service) gameOf(userName string) *game {
+	for _, g := range s.games {
+		if g.status == inProgress && (g.user1 == userName || g.user2 == userName) {
+			return g
+		}
+	}
+	return nil
+}
+
+func (g *game) opponent(userName string) string {
+	if g.user1 == userName {
+		return g.user2
+	}
+	return g.user1
+}
+
+// PlaceFleet validates the fleet of the user and starts the battle once both fleets are placed
+func (s *Service) PlaceFleet(userName string, fleet [][]Point) error {
+	const op = "Service.PlaceFleet"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_name", userName),
+	)
+
+	s.mu.Lock()
+
+	g := s.gameOf(userName)
+	if g == nil {
+		s.mu.Unlock()
+		return ErrGameNotFound
+	}
+	if g.boards[userName] != nil {
+		s.mu.Unlock()
+		return ErrFleetPlaced
+	}
+
+	b, err := newBoard(fleet)
+	if err != nil {
+		s.mu.Unlock()
+		log.Info("fleet rejected")
+		return err
+	}
+	g.boards[userName] = b
+	g.lastSeen[userName] = time.Now()
+	g.record(ReplayEvent{Type: ReplayFleet, Player: userName, Fleet: b.fleet()})
+
+	if len(g.boards) < 2 {
+		s.mu.Unlock()
+		log.Info("fleet placed, waiting for the opponent")
+		return nil
+	}
+
+	g.turn = g.user1
+	if rand.Intn(2) == 1 {
+		g.turn = g.user2
+	}
+	g.deadline = time.Now().Add(s.timeouts.Turn)
+	first, second := g.turn, g.opponent(g.turn)
+	id := g.id
+	s.mu.Unlock()
+
+	log.Info("battle started", slog.String("first", first))
+	s.notify(
+		Event{Type: EventReady, To: first, GameID: id, First: true},
+		Event{Type: EventReady, To: second, GameID: id},
+		Event{Type: EventReady, GameID: id, Attacker: first}, // the spectators learn who shoots first
+	)
+	return nil
+}
+
+// Attack resolves the shot of the user at the opponent's sea and notifies both players
+func (s *Service) Attack(ctx context.Context, userName string, x, y int) error {
+	const op = "Service.Attack"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_name", userName),
+	)
+
+	s.mu.Lock()
+
+	g := s.gameOf(userName)
+	if g == nil {
+		s.mu.Unlock()
+		return ErrGameNotFound
+	}
+	g.lastSeen[userName] = time.Now()
+	if g.turn == "" {
+		s.mu.Unlock()
+		return ErrNotStarted
+	}
+	if g.turn != userName {
+		s.mu.Unlock()
+		return ErrNotYourTurn
+	}
+
+	defender := g.opponent(userName)
+	hit, destroy, err := g.boards[defender].shoot(Point{X: x, Y: y})
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	g.shots++
+	g.record(ReplayEvent{Type: ReplayShot, Player: userName, X: x, Y: y, Hit: hit, Destroy: destroy})
+
+	shot := Event{Type: EventShot, GameID: g.id, Attacker: userName, X: x, Y: y, Hit: hit, Destroy: destroy}
+	var (
+		match  Match
+		fleets map[string][][]Point
+	)
+	if g.boards[defender].allDestroyed() {
+		g.status = finished
+		g.winner = userName
+		g.turn = ""
+		g.endedAt = time.Now()
+		shot.Type = EventEnd
+		shot.Winner = userName
+		shot.Reason = EndAllSunk
+		g.record(ReplayEvent{Type: ReplayEnd, Player: userName, Reason: EndAllSunk})
+		match = g.match(userName, defender, EndAllSunk)
+		fleets = g.fleets()
+	} else {
+		g.turn = defender
+		g.deadline = time.Now().Add(s.timeouts.Turn)
+	}
+	s.mu.Unlock()
+
+	if shot.Type == EventEnd {
+		log.Info("battle finished", slog.String("loser", defender))
+		err = s.recordResult(ctx, match)
+		if err != nil {
+			log.Error("failed to record the result", slog.String("error", err.Error()))
+		}
+	}
+
+	toAttacker, toDefender, toSpectators := shot, shot, shot
+	toAttacker.To, toDefender.To = userName, defender
+	toSpectators.Fleets = fleets
+	s.notify(toAttacker, toDefender, toSpectators)
+	return nil
+}
+
+// forfeit finishes the game in favour of the opponent of the loser. Must be called with s.mu held,
+// the returned events must be sent and the match recorded after it is released.
+func (s *Service) forfeit(g *game, loser string, reason EndReason) (Match, []Event) {
+	winner := g.opponent(loser)
+	g.status = finished
+	g.winner = winner
+	g.turn = ""
+	g.endedAt = time.Now()
+	g.record(ReplayEvent{Type: ReplayEnd, Player: winner, Reason: reason})
+	return g.match(winner, loser, reason), []Event{
+		{Type: EventEnd, To: winner, GameID: g.id, Winner: winner, Reason: reason},
+		{Type: EventEnd, To: loser, GameID: g.id, Winner: winner, Reason: reason},
+		{Type: EventEnd, GameID: g.id, Winner: winner, Reason: reason, Fleets: g.fleets()},
+	}
+}
+
+// fleets returns the ships of the players who have placed their fleets. Must be called with s.mu held.
+func (g *game) fleets() map[string][][]Point {
+	fleets := make(map[string][][]Point, len(g.boards))
+	for user, b := range g.boards {
+		fleets[user] = b.fleet()
+	}
+	return fleets
+}
@@ -0,0 +1,272 @@
+package game_test
+
+import (
+	"battle-ship_server/internal/service/game"
+	"battle-ship_server/internal/service/game/rating"
+	"battle-ship_server/internal/storage/memory"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+var ctx = context.Background()
+
+// fleet is a valid fleet, the ships do not touch and the rows from 6 on are water
+var fleet = [][]game.Point{
+	{{0, 0}, {1, 0}, {2, 0}, {3, 0}},
+	{{5, 0}, {6, 0}, {7, 0}},
+	{{9, 0}, {9, 1}, {9, 2}},
+	{{0, 2}, {1, 2}},
+	{{3, 2}, {4, 2}},
+	{{6, 2}, {7, 2}},
+	{{0, 4}},
+	{{2, 4}},
+	{{4, 4}},
+	{{6, 4}},
+}
+
+// withShip returns the fleet with the ship i replaced
+func withShip(i int, cells ...game.Point) [][]game.Point {
+	f := append([][]game.Point(nil), fleet...)
+	f[i] = cells
+	return f
+}
+
+// newService returns the service with a started game of alice and bob and the channel of its events.
+// The events are read all the time, so the service never waits for the test.
+func newService(t *testing.T) (*game.Service, <-chan game.Event) {
+	t.Helper()
+	storage := memory.New()
+	for _, login := range []string{"alice", "bob"} {
+		err := storage.SaveUser(ctx, login, []byte("hash"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := game.New(storage, slog.New(slog.NewTextHandler(io.Discard, nil)), rating.NewElo(1500, 32),
+		game.Timeouts{Heartbeat: time.Hour, Placement: time.Hour, Turn: time.Hour},
+		game.Matchmaking{Interval: time.Hour})
+	t.Cleanup(s.Close)
+
+	events := make(chan game.Event, 1024)
+	go func() {
+		for e := range s.Events() {
+			events <- e
+		}
+	}()
+
+	err := s.CreateGame("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.JoinGame("alice", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, events
+}
+
+// startBattle places both fleets and returns the players in the order they shoot
+func startBattle(t *testing.T, s *game.Service, events <-chan game.Event) (first, second string) {
+	t.Helper()
+	for _, login := range []string{"alice", "bob"} {
+		err := s.PlaceFleet(login, fleet)
+		if err != nil {
+			t.Fatalf("PlaceFleet(%s): %v", login, err)
+		}
+	}
+	for {
+		e := nextEvent(t, events)
+		if e.Type == game.EventReady && e.First {
+			first = e.To
+			break
+		}
+	}
+	if first == "alice" {
+		return "alice", "bob"
+	}
+	return "bob", "alice"
+}
+
+// nextEvent waits for the next event of the service
+func nextEvent(t *testing.T, events <-chan game.Event) game.Event {
+	t.Helper()
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("no event")
+		return game.Event{}
+	}
+}
+
+func TestPlaceFleet(t *testing.T) {
+	tests := []struct {
+		name  string
+		fleet [][]game.Point
+		want  error
+	}{
+		{"valid", fleet, nil},
+		{"touching ships", withShip(7, game.Point{X: 1, Y: 4}), game.ErrBadFleet},
+		{"touching ships at the end", withShip(7, game.Point{X: 9, Y: 3}), game.ErrBadFleet},
+		{"overlapping ships", withShip(7, game.Point{X: 0, Y: 4}), game.ErrBadFleet},
+		{"diagonal ship", withShip(3, game.Point{X: 0, Y: 7}, game.Point{X: 1, Y: 8}), game.ErrBadFleet},
+		{"ship with a gap", withShip(3, game.Point{X: 0, Y: 7}, game.Point{X: 2, Y: 7}), game.ErrBadFleet},
+		{"ship out of the sea", withShip(7, game.Point{X: 10, Y: 7}), game.ErrBadFleet},
+		{"wrong ship sizes", withShip(7, game.Point{X: 2, Y: 7}, game.Point{X: 3, Y: 7}), game.ErrBadFleet},
+		{"missing ship", fleet[:len(fleet)-1], game.ErrBadFleet},
+		{"extra ship", append(fleet[:len(fleet):len(fleet)], []game.Point{{8, 8}}), game.ErrBadFleet},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newService(t)
+			err := s.PlaceFleet("alice", tt.fleet)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("PlaceFleet = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttack(t *testing.T) {
+	tests := []struct {
+		name string
+		// shots are made in turn by the first and the second player, the last one must fail with want
+		shots     []game.Point
+		outOfTurn bool // the last shot is made by the player who has just shot
+		want      error
+	}{
+		{"miss", []game.Point{{5, 5}}, false, nil},
+		{"out of turn", []game.Point{{5, 5}, {5, 6}}, true, game.ErrNotYourTurn},
+		{"repeated cell", []game.Point{{5, 5}, {5, 5}, {5, 5}}, false, game.ErrAlreadyShot},
+		{"repeated hit", []game.Point{{0, 0}, {5, 5}, {0, 0}}, false, game.ErrAlreadyShot},
+		{"out of the sea", []game.Point{{10, 0}}, false, game.ErrBadShot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, events := newService(t)
+			first, second := startBattle(t, s, events)
+			players := []string{first, second}
+
+			var err error
+			for i, p := range tt.shots {
+				shooter := players[i%2]
+				if tt.outOfTurn && i == len(tt.shots)-1 {
+					shooter = players[(i+1)%2]
+				}
+				err = s.Attack(ctx, shooter, p.X, p.Y)
+				if i < len(tt.shots)-1 && err != nil {
+					t.Fatalf("shot %d at %v: %v", i, p, err)
+				}
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Attack = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttackBeforeBattle(t *testing.T) {
+	s, _ := newService(t)
+	err := s.Attack(ctx, "alice", 0, 0)
+	if !errors.Is(err, game.ErrNotStarted) {
+		t.Errorf("Attack = %v, want %v", err, game.ErrNotStarted)
+	}
+}
+
+func TestSinkingLastShipEndsGame(t *testing.T) {
+	s, events := newService(t)
+	first, second := startBattle(t, s, events)
+
+	var cells []game.Point
+	for _, ship := range fleet {
+		cells = append(cells, ship...)
+	}
+	for i, p := range cells {
+		err := s.Attack(ctx, first, p.X, p.Y)
+		if err != nil {
+			t.Fatalf("shot at %v: %v", p, err)
+		}
+		if i == len(cells)-1 {
+			break
+		}
+		err = s.Attack(ctx, second, i%10, 6+i/10)
+		if err != nil {
+			t.Fatalf("miss %d: %v", i, err)
+		}
+	}
+
+	var end game.Event
+	for end.Type != game.EventEnd {
+		end = nextEvent(t, events)
+	}
+	if end.Winner != first || end.Reason != game.EndAllSunk {
+		t.Errorf("end = %s by %s, want %s by %s", end.Winner, end.Reason, first, game.EndAllSunk)
+	}
+	if err := s.Attack(ctx, second, 9, 9); !errors.Is(err, game.ErrGameNotFound) {
+		t.Errorf("Attack after the end = %v, want %v", err, game.ErrGameNotFound)
+	}
+
+	stat, err := s.GetUserStat(ctx, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Wins != 1 || stat.Rating.Value != 1516 {
+		t.Errorf("winner stat = %+v, want 1 win and the rating 1516", stat)
+	}
+	err = s.SaveGameResult(second, first, second)
+	if err != nil {
+		t.Errorf("SaveGameResult = %v", err)
+	}
+}
+
+func TestNewGameDuringBattle(t *testing.T) {
+	tests := []struct {
+		name    string
+		newGame func(s *game.Service) error
+	}{
+		{"create", func(s *game.Service) error {
+			return s.CreateGame("bob")
+		}},
+		{"join", func(s *game.Service) error {
+			err := s.CreateGame("carol")
+			if err != nil {
+				return err
+			}
+			return s.JoinGame("carol", "bob")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, events := newService(t)
+			err := tt.newGame(s)
+			if !errors.Is(err, game.ErrAlreadyPlaying) {
+				t.Fatalf("new game = %v, want %v", err, game.ErrAlreadyPlaying)
+			}
+
+			// the battle goes on
+			first, _ := startBattle(t, s, events)
+			err = s.Attack(ctx, first, 5, 5)
+			if err != nil {
+				t.Errorf("Attack = %v", err)
+			}
+		})
+	}
+}
+
+func TestJoinWhileWaiting(t *testing.T) {
+	s, _ := newService(t)
+	for _, login := range []string{"carol", "dave"} {
+		err := s.CreateGame(login)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := s.JoinGame("carol", "dave")
+	if !errors.Is(err, game.ErrAlreadyPlaying) {
+		t.Errorf("JoinGame = %v, want %v", err, game.ErrAlreadyPlaying)
+	}
+}
@@ -0,0 +1,175 @@
+package game
+
+import "errors"
+
+var (
+	ErrBadFleet    = errors.New("invalid fleet placement")
+	ErrBadShot     = errors.New("coordinates out of the sea")
+	ErrAlreadyShot = errors.New("cell already attacked")
+)
+
+const seaSize = 10
+
+// fleetShips is the number of ships of every size (in decks) a fleet must contain
+var fleetShips = map[int]int{
+	1: 4,
+	2: 3,
+	3: 2,
+	4: 1,
+}
+
+type Point struct {
+	X int
+	Y int
+}
+
+func (p Point) inSea() bool {
+	return p.X >= 0 && p.X < seaSize && p.Y >= 0 && p.Y < seaSize
+}
+
+type ship struct {
+	cells []Point
+	hits  int
+}
+
+func (s *ship) destroyed() bool {
+	return s.hits == len(s.cells)
+}
+
+// board is the sea of one player as the server sees it
+type board struct {
+	ships []*ship
+	cells [seaSize][seaSize]*ship // [y][x], nil is water
+	shots [seaSize][seaSize]bool  // [y][x]
+	alive int
+}
+
+// newBoard validates the fleet and places it on a new board.
+// Ships must be straight lines of adjacent cells and must not touch each other.
+func newBoard(fleet [][]Point) (*board, error) {
+	b := &board{}
+	cnt := make(map[int]int)
+
+	for _, cells := range fleet {
+		if !straight(cells) {
+			return nil, ErrBadFleet
+		}
+		s := &ship{cells: cells}
+		for _, p := range cells {
+			if b.cells[p.Y][p.X] != nil {
+				return nil, ErrBadFleet
+			}
+			b.cells[p.Y][p.X] = s
+		}
+		b.ships = append(b.ships, s)
+		cnt[len(cells)]++
+	}
+
+	for decks, n := range fleetShips {
+		if cnt[decks] != n {
+			return nil, ErrBadFleet
+		}
+	}
+	if len(cnt) != len(fleetShips) {
+		return nil, ErrBadFleet
+	}
+
+	// ships must not touch each other
+	for y := 0; y < seaSize; y++ {
+		for x := 0; x < seaSize; x++ {
+			s := b.cells[y][x]
+			if s == nil {
+				continue
+			}
+			for _, n := range []Point{{x + 1, y}, {x - 1, y}, {x, y + 1}, {x, y - 1}} {
+				if n.inSea() && b.cells[n.Y][n.X] != nil && b.cells[n.Y][n.X] != s {
+					return nil, ErrBadFleet
+				}
+			}
+		}
+	}
+
+	b.alive = len(b.ships)
+	return b, nil
+}
+
+// straight reports whether the cells are in the sea and form a horizontal or vertical line without gaps
+func straight(cells []Point) bool {
+	if len(cells) == 0 {
+		return false
+	}
+	minX, maxX, minY, maxY := cells[0].X, cells[0].X, cells[0].Y, cells[0].Y
+	for _, p := range cells {
+		if !p.inSea() {
+			return false
+		}
+		minX, maxX = min(minX, p.X), max(maxX, p.X)
+		minY, maxY = min(minY, p.Y), max(maxY, p.Y)
+	}
+	if minX != maxX && minY != maxY {
+		return false
+	}
+	// duplicates are caught by the overlap check, so the length is enough here
+	return (maxX-minX)+(maxY-minY)+1 == len(cells)
+}
+
+// shoot resolves a shot at p
+func (b *board) shoot(p Point) (hit, destroy bool, err error) {
+	if !p.inSea() {
+		return false, false, ErrBadShot
+	}
+	if b.shots[p.Y][p.X] {
+		return false, false, ErrAlreadyShot
+	}
+	b.shots[p.Y][p.X] = true
+
+	s := b.cells[p.Y][p.X]
+	if s == nil {
+		return false, false, nil
+	}
+	s.hits++
+	if s.destroyed() {
+		b.alive--
+		return true, true, nil
+	}
+	return true, false, nil
+}
+
+func (b *board) allDestroyed() bool {
+	return b.alive == 0
+}
+
+// Shot is a resolved shot at a sea
+type Shot struct {
+	Point
+	Hit     bool
+	Destroy bool // the ship of the cell is sunk
+}
+
+// resolvedShots returns all shots made at the board
+func (b *board) resolvedShots() []Shot {
+	var shots []Shot
+	for y := 0; y < seaSize; y++ {
+		for x := 0; x < seaSize; x++ {
+			if !b.shots[y][x] {
+				continue
+			}
+			shot := Shot{Point: Point{X: x, Y: y}}
+			if s := b.cells[y][x]; s != nil {
+				shot.Hit = true
+				shot.Destroy = s.destroyed()
+			}
+			shots = append(shots, shot)
+		}
+	}
+	return shots
+}
+
+// fleet returns the cells of every ship of the board
+func (b *board) fleet() [][]Point {
+	fleet := make([][]Point, 0, len(b.ships))
+	for _, s := range b.ships {
+		fleet = append(fleet, s.cells)
+	}
+	return fleet
+}
@@ -0,0 +1,79 @@
+package game
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrChatEmpty   = errors.New("empty chat message")
+	ErrChatTooLong = errors.New("chat message is too long")
+	ErrChatTooFast = errors.New("too many chat messages, wait a little")
+)
+
+const (
+	maxChatLength = 200 // runes
+	chatLimit     = 5   // messages a player may send within chatWindow
+	chatWindow    = 10 * time.Second
+)
+
+// Chat passes the message of the user to the opponent in the running game
+func (s *Service) Chat(userName, text string) error {
+	const op = "Service.Chat"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_name", userName),
+	)
+
+	// control characters could mess up the opponent's terminal
+	text = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text))
+	if text == "" {
+		return ErrChatEmpty
+	}
+	if utf8.RuneCountInString(text) > maxChatLength {
+		return ErrChatTooLong
+	}
+
+	s.mu.Lock()
+
+	g := s.gameOf(userName)
+	if g == nil {
+		s.mu.Unlock()
+		return ErrGameNotFound
+	}
+	now := time.Now()
+	g.lastSeen[userName] = now
+
+	recent := g.chatTimes[userName][:0]
+	for _, t := range g.chatTimes[userName] {
+		if now.Sub(t) < chatWindow {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= chatLimit {
+		g.chatTimes[userName] = recent
+		s.mu.Unlock()
+		log.Info("chat message rejected, rate limit")
+		return ErrChatTooFast
+	}
+	if g.chatTimes == nil {
+		g.chatTimes = make(map[string][]time.Time)
+	}
+	g.chatTimes[userName] = append(recent, now)
+	opponent := g.opponent(userName)
+	s.mu.Unlock()
+
+	log.Info("chat message", slog.String("to", opponent), slog.String("text", text))
+	s.notify(Event{Type: EventChat, To: opponent, From: userName, Text: text})
+	return nil
+}
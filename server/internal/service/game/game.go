@@ -1,18 +1,35 @@
 package game
 
 import (
+	"battle-ship_server/internal/service/game/rating"
+	"context"
 	"errors"
 	"log/slog"
 	"sync"
+	"time"
 
-	"github.com/streadway/amqp"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrSelfPlay is returned when the user tries to join their own game
+	ErrSelfPlay = errors.New("you can't play against yourself")
+	// ErrBattleStarted is returned when the user deletes the game whose battle has begun
+	ErrBattleStarted = errors.New("the battle has begun, forfeit the game instead")
 )
 
 type Service struct {
 	Storage StatStorage
 	log     *slog.Logger
-	games   map[string]game // user name -> game
+	rating  rating.Calculator
+	games   map[string]*game // creator user name -> game
 	mu      sync.RWMutex
+	events  chan Event
+
+	timeouts    Timeouts
+	matchmaking Matchmaking
+	seekers     map[string]*seeker // user name -> player waiting for a quick match
+	done        chan struct{}
 }
 
 type gameStatus int
@@ -20,54 +37,168 @@ type gameStatus int
 const (
 	wait       gameStatus = iota // 0
 	inProgress                   // 1
+	finished                     // 2
 )
 
 type Statistics struct {
 	Wins   int
 	Losses int
-	Rating int
+	Rating rating.Rating
+}
+
+// Result is the outcome of a finished game
+type Result struct {
+	Winner string
+	Loser  string
 }
 
+// ApplyResult updates the statistics of the players and the match by the result of the match.
+// The storage calls it with the statistics locked until they are saved.
+type ApplyResult func(winner, loser *Statistics, match *Match)
+
 type StatStorage interface {
-	UpdateStat(login string, stat Statistics) error
-	GetStat(login string) (Statistics, error)
+	UpdateStat(ctx context.Context, login string, stat Statistics) error
+	GetStat(ctx context.Context, login string) (Statistics, error)
+	RecordResult(ctx context.Context, match Match, apply ApplyResult) error                                  // atomically, with the events of the match
+	GetReplay(ctx context.Context, matchID int64) (Match, error)                                             // ErrMatchNotFound if there is no such match
+	GetMatches(ctx context.Context, login string, limit, offset int) (matches []Match, total int, err error) // the latest first
+	GetResults(ctx context.Context) ([]Result, error)                                                        // in the order the games were finished
+	ResetRatings(ctx context.Context, initial rating.Rating) error
+	// GetLeaderboard returns the players who have played at least once,
+	// ordered by rating, then by wins, losses and login
+	GetLeaderboard(ctx context.Context, limit, offset int) (entries []LeaderboardEntry, total int, err error)
+	// GetRank returns the position of the player in the leaderboard, 0 if the player is not there
+	GetRank(ctx context.Context, login string) (int, error)
 }
 
 type game struct {
+	id     string // set when the game starts
 	user1  string
-	dUser1 *amqp.Delivery
 	user2  string
-	dUser2 *amqp.Delivery
 	status gameStatus
+
+	boards    map[string]*board // user name -> sea of the user
+	turn      string            // user name of the player who shoots next
+	winner    string
+	startedAt time.Time
+	endedAt   time.Time
+	shots     int
+
+	lastSeen map[string]time.Time // user name -> time of the last request or heartbeat of the user
+	deadline time.Time            // the fleets must be placed or the next shot made by this time
+
+	chatTimes map[string][]time.Time // user name -> times of the recent chat messages of the user
+	replay    []ReplayEvent
 }
 
-func New(storage StatStorage, log *slog.Logger) *Service {
-	return &Service{
-		log:     log,
-		games:   make(map[string]game),
-		Storage: storage,
+func New(storage StatStorage, log *slog.Logger, ratingCalc rating.Calculator, timeouts Timeouts, matchmaking Matchmaking) *Service {
+	s := &Service{
+		log:         log,
+		rating:      ratingCalc,
+		games:       make(map[string]*game),
+		Storage:     storage,
+		events:      make(chan Event, 64),
+		timeouts:    timeouts,
+		matchmaking: matchmaking,
+		seekers:     make(map[string]*seeker),
+		done:        make(chan struct{}),
 	}
+	go s.matchmaker()
+	go s.watchdog()
+	return s
 }
 
-func (s *Service) CreateGame(userName string, dUser *amqp.Delivery) error {
+// Close stops the background work of the service
+func (s *Service) Close() {
+	close(s.done)
+}
+
+// CreateGame makes the user wait for an opponent, the user leaves the quick match queue.
+// Both players get EventStarted once another user joins. The game the user is waiting in is replaced,
+// the running one must be finished first.
+func (s *Service) CreateGame(userName string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.games[userName] = game{
+	if s.gameOf(userName) != nil {
+		return ErrAlreadyPlaying
+	}
+	delete(s.seekers, userName)
+	s.games[userName] = &game{
 		user1:  userName,
-		dUser1: dUser,
 		status: wait,
+		boards: make(map[string]*board),
 	}
 	return nil
 }
 
+// DelGame deletes the game created by the user. The opponent who has joined it meanwhile is told
+// the game is cancelled, unless the battle has begun: it can only be forfeited then.
 func (s *Service) DelGame(userName string) (user2 string, err error) {
+	const op = "Service.DelGame"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_name", userName),
+	)
+
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
-	user2 = s.games[userName].user2
+	g, ok := s.games[userName]
+	if !ok {
+		s.mu.Unlock()
+		return "", nil
+	}
+	if g.status == inProgress && g.turn != "" {
+		s.mu.Unlock()
+		return "", ErrBattleStarted
+	}
 	delete(s.games, userName)
-	return user2, nil
+	if g.status != inProgress {
+		s.mu.Unlock()
+		return g.user2, nil
+	}
+	events := []Event{
+		{Type: EventEnd, To: g.user1, GameID: g.id, Reason: EndCancelled},
+		{Type: EventEnd, To: g.user2, GameID: g.id, Reason: EndCancelled},
+		{Type: EventEnd, GameID: g.id, Reason: EndCancelled, Fleets: g.fleets()},
+	}
+	s.mu.Unlock()
+
+	log.Info("game cancelled by the creator", slog.String("user2", g.user2))
+	s.notify(events...)
+	return g.user2, nil
+}
+
+// LeaveGames deletes the game the user is waiting in, takes the user out of the quick match queue
+// and forfeits the running game of the user
+func (s *Service) LeaveGames(ctx context.Context, userName string) error {
+	const op = "Service.LeaveGames"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_name", userName),
+	)
+
+	s.mu.Lock()
+
+	if g, ok := s.games[userName]; ok && g.status == wait {
+		delete(s.games, userName)
+		log.Info("waiting game deleted")
+	}
+	delete(s.seekers, userName)
+
+	g := s.gameOf(userName)
+	if g == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	match, events := s.forfeit(g, userName, EndForfeit)
+	s.mu.Unlock()
+
+	log.Info("game forfeited", slog.String("winner", match.Winner))
+	s.notify(events...)
+	return s.recordResult(ctx, match)
 }
 
 func (s *Service) GetAvailableGames() ([]string, error) {
@@ -83,67 +214,142 @@ func (s *Service) GetAvailableGames() ([]string, error) {
 	return games, nil
 }
 
-func (s *Service) JoinGame(creatorUserName, joiningUserName string, dJoiningUser *amqp.Delivery) (dCreatorUserName *amqp.Delivery, err error) {
+// JoinGame starts the waiting game of the creator, the joining user leaves the quick match queue.
+// The joining user must not wait in a game of their own or play one. Both players get EventStarted.
+func (s *Service) JoinGame(creatorUserName, joiningUserName string) error {
 	if creatorUserName == joiningUserName {
-		return dJoiningUser, errors.New("you can't play against yourself")
+		return ErrSelfPlay
 	}
 
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
-	ugame := s.games[creatorUserName]
-	s.games[creatorUserName] = game{
-		user1:  ugame.user1,
-		dUser1: ugame.dUser1,
-		user2:  joiningUserName,
-		dUser2: dJoiningUser,
-		status: inProgress,
+	ugame, ok := s.games[creatorUserName]
+	if !ok || ugame.status != wait {
+		s.mu.Unlock()
+		return ErrGameNotFound
+	}
+	if s.busy(joiningUserName) {
+		s.mu.Unlock()
+		return ErrAlreadyPlaying
 	}
-	return ugame.dUser1, nil
+	delete(s.seekers, joiningUserName)
+	ugame.user2 = joiningUserName
+	s.start(ugame, time.Now())
+	events := started(ugame, 0, 0)
+	s.mu.Unlock()
+
+	s.notify(events...)
+	return nil
 }
 
-func (s *Service) SaveGameResult(winner string, loser string) error {
-	const op = "Service.ProcessGameResult"
+// start begins the game of the two players. Must be called with s.mu held.
+func (s *Service) start(g *game, now time.Time) {
+	g.id = uuid.NewString()
+	g.status = inProgress
+	g.startedAt = now
+	g.lastSeen = map[string]time.Time{g.user1: now, g.user2: now}
+	g.deadline = now.Add(s.timeouts.Placement)
+}
+
+// busy tells if the user waits for an opponent or plays. Must be called with s.mu held.
+func (s *Service) busy(userName string) bool {
+	if g, ok := s.games[userName]; ok && g.status == wait {
+		return true
+	}
+	return s.gameOf(userName) != nil
+}
+
+// started returns the events telling both players of the game who they play against
+func started(g *game, rating1, rating2 float64) []Event {
+	return []Event{
+		{Type: EventStarted, To: g.user1, GameID: g.id, Opponent: g.user2, OpponentRating: rating2},
+		{Type: EventStarted, To: g.user2, GameID: g.id, Opponent: g.user1, OpponentRating: rating1},
+	}
+}
+
+// SaveGameResult confirms the result of a finished game reported by one of its players.
+// The result itself is recorded by the service when the last ship is destroyed,
+// so only a result that matches the tracked game is accepted.
+func (s *Service) SaveGameResult(submitter, winner, loser string) error {
+	const op = "Service.SaveGameResult"
 
 	log := s.log.With(
 		slog.String("op", op),
+		slog.String("submitter", submitter),
 		slog.String("winner", winner),
 		slog.String("loser", loser),
 	)
 
-	winnerStat, err := s.Storage.GetStat(winner)
-	if err != nil {
-		log.Error(err.Error())
-		return err
-	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	loserStat, err := s.Storage.GetStat(loser)
-	if err != nil {
-		log.Error(err.Error())
-		return err
+	creator, g := s.gameBetween(winner, loser)
+	switch {
+	case g == nil:
+		log.Info("result for unknown game rejected")
+		return ErrGameNotFound
+	case g.status == wait:
+		log.Info("result for not started game rejected")
+		return ErrNotStarted
+	case submitter != g.user1 && submitter != g.user2:
+		log.Warn("result from non-participant rejected")
+		return ErrNotParticipant
+	case g.status != finished:
+		log.Info("result for unfinished game rejected")
+		return ErrNotFinished
+	case g.winner != winner:
+		log.Warn("wrong result rejected", slog.String("actual_winner", g.winner))
+		return ErrWrongResult
 	}
 
-	winnerStat.Wins++
-	winnerStat.Rating += loserStat.Rating / 10
-	loserStat.Losses++
-	loserStat.Rating -= winnerStat.Rating / 10
+	delete(s.games, creator)
+	return nil
+}
 
-	err = s.Storage.UpdateStat(winner, winnerStat)
-	if err != nil {
-		log.Error(err.Error())
-		return err
+// gameBetween returns the game of the two users and the name of its creator. Must be called with s.mu held.
+func (s *Service) gameBetween(user1, user2 string) (creator string, g *game) {
+	for creator, g := range s.games {
+		if (g.user1 == user1 && g.user2 == user2) || (g.user1 == user2 && g.user2 == user1) {
+			return creator, g
+		}
 	}
+	return "", nil
+}
+
+// recordResult updates the statistics of both players of a finished game and saves the match
+func (s *Service) recordResult(ctx context.Context, match Match) error {
+	const op = "Service.recordResult"
 
-	err = s.Storage.UpdateStat(loser, loserStat)
+	winner, loser := match.Winner, match.Loser
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("winner", winner),
+		slog.String("loser", loser),
+	)
+
+	var winnerRating, loserRating float64
+	err := s.Storage.RecordResult(ctx, match, func(winnerStat, loserStat *Statistics, match *Match) {
+		oldWinner, oldLoser := s.currentRating(*winnerStat), s.currentRating(*loserStat)
+		winnerStat.Rating, loserStat.Rating = s.rating.Rate(oldWinner, oldLoser)
+		match.WinnerRatingDelta = winnerStat.Rating.Value - oldWinner.Value
+		match.LoserRatingDelta = loserStat.Rating.Value - oldLoser.Value
+		winnerStat.Wins++
+		loserStat.Losses++
+		winnerRating, loserRating = winnerStat.Rating.Value, loserStat.Rating.Value
+	})
 	if err != nil {
 		log.Error(err.Error())
 		return err
 	}
 
+	log.Info("result recorded",
+		slog.Float64("winner_rating", winnerRating),
+		slog.Float64("loser_rating", loserRating),
+	)
 	return nil
 }
 
-func (s *Service) GetUserStat(userName string) (Statistics, error) {
+func (s *Service) GetUserStat(ctx context.Context, userName string) (Statistics, error) {
 	const op = "Service.GetUserStat"
 
 	log := s.log.With(
@@ -151,11 +357,12 @@ func (s *Service) GetUserStat(userName string) (Statistics, error) {
 		slog.String("user_name", userName),
 	)
 
-	stat, err := s.Storage.GetStat(userName)
+	stat, err := s.Storage.GetStat(ctx, userName)
 	if err != nil {
 		log.Error(err.Error())
 		return Statistics{}, err
 	}
+	stat.Rating = s.currentRating(stat)
 
 	return stat, nil
 }
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Match is a finished game as it is kept in the history
+type Match struct {
+	ID                int64
+	Winner            string
+	Loser             string
+	StartedAt         time.Time
+	EndedAt           time.Time
+	Shots             int
+	WinnerRatingDelta float64
+	LoserRatingDelta  float64
+	EndReason         EndReason
+	Events            []ReplayEvent // filled in to be saved and for the replay only
+}
+
+func (m Match) Duration() time.Duration {
+	return m.EndedAt.Sub(m.StartedAt)
+}
+
+// match describes the game finished right now, the end must be recorded before. Must be called with s.mu held.
+func (g *game) match(winner, loser string, reason EndReason) Match {
+	return Match{
+		Winner:    winner,
+		Loser:     loser,
+		StartedAt: g.startedAt,
+		EndedAt:   time.Now(),
+		Shots:     g.shots,
+		EndReason: reason,
+		Events:    g.replay,
+	}
+}
+
+const maxHistoryPageSize = 50
+
+// GetHistory returns a page of the finished games of the user, the latest first, and the number of all of them
+func (s *Service) GetHistory(ctx context.Context, userName string, page, pageSize int) (matches []Match, total int, err error) {
+	const op = "Service.GetHistory"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_name", userName),
+	)
+
+	page = max(page, 1)
+	pageSize = min(max(pageSize, 1), maxHistoryPageSize)
+
+	matches, total, err = s.Storage.GetMatches(ctx, userName, pageSize, (page-1)*pageSize)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, 0, err
+	}
+
+	return matches, total, nil
+}
@@ -0,0 +1,179 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+)
+
+var (
+	ErrNotQueued      = errors.New("you are not in the quick match queue")
+	ErrAlreadyPlaying = errors.New("you are already in a game")
+)
+
+// Matchmaking sets how quick match opponents are chosen
+type Matchmaking struct {
+	InitialGap float64       // rating gap accepted right after the player joins the queue
+	GapGrowth  float64       // rating points added to the gap every second of waiting
+	MaxGap     float64       // the gap never grows beyond it, 0 means no limit
+	Interval   time.Duration // how often the queue is checked for the players whose gap has grown
+}
+
+// seeker is a player waiting in the quick match queue
+type seeker struct {
+	userName string
+	rating   float64
+	since    time.Time
+}
+
+// pairing is a game created by the quick match
+type pairing struct {
+	game    *game
+	rating1 float64
+	rating2 float64
+}
+
+// QuickMatch puts the user into the quick match queue. The user is paired with the waiting player
+// of the closest rating as soon as the gap between them is acceptable for both.
+// Both players get EventStarted with the rating of the opponent. A user who waits for an opponent or plays can't join the queue.
+func (s *Service) QuickMatch(ctx context.Context, userName string) error {
+	const op = "Service.QuickMatch"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_name", userName),
+	)
+
+	stat, err := s.GetUserStat(ctx, userName)
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	s.mu.Lock()
+	if s.busy(userName) {
+		s.mu.Unlock()
+		return ErrAlreadyPlaying
+	}
+	s.seekers[userName] = &seeker{
+		userName: userName,
+		rating:   stat.Rating.Value,
+		since:    time.Now(),
+	}
+	pairs := s.pairSeekers(time.Now())
+	s.mu.Unlock()
+
+	log.Info("joined the quick match queue", slog.Float64("rating", stat.Rating.Value))
+	s.announce(pairs)
+	return nil
+}
+
+// CancelQuickMatch takes the user out of the quick match queue
+func (s *Service) CancelQuickMatch(userName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.seekers[userName]; !ok {
+		return ErrNotQueued
+	}
+	delete(s.seekers, userName)
+	return nil
+}
+
+// matchmaker pairs the waiting players as their acceptable rating gap grows until the service is closed
+func (s *Service) matchmaker() {
+	ticker := time.NewTicker(s.matchmaking.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			pairs := s.pairSeekers(now)
+			s.mu.Unlock()
+			s.announce(pairs)
+		}
+	}
+}
+
+// gap returns the rating gap the seeker accepts at the moment
+func (s *Service) gap(sk *seeker, now time.Time) float64 {
+	gap := s.matchmaking.InitialGap + s.matchmaking.GapGrowth*now.Sub(sk.since).Seconds()
+	if s.matchmaking.MaxGap > 0 {
+		gap = min(gap, s.matchmaking.MaxGap)
+	}
+	return gap
+}
+
+// pairSeekers starts the games of the seekers that can be matched, the longest waiting first.
+// The seekers who have got a game meanwhile are left in the queue until it is over. Must be called with s.mu held.
+func (s *Service) pairSeekers(now time.Time) []pairing {
+	queue := make([]*seeker, 0, len(s.seekers))
+	for _, sk := range s.seekers {
+		if !s.busy(sk.userName) {
+			queue = append(queue, sk)
+		}
+	}
+	sort.Slice(queue, func(i, j int) bool {
+		return queue[i].since.Before(queue[j].since)
+	})
+
+	var pairs []pairing
+	for _, a := range queue {
+		if _, ok := s.seekers[a.userName]; !ok {
+			continue // already paired
+		}
+
+		var b *seeker
+		bestDiff := math.Inf(1)
+		for _, c := range queue {
+			if c == a {
+				continue
+			}
+			if _, ok := s.seekers[c.userName]; !ok {
+				continue
+			}
+			diff := math.Abs(a.rating - c.rating)
+			if diff <= s.gap(a, now) && diff <= s.gap(c, now) && diff < bestDiff {
+				b, bestDiff = c, diff
+			}
+		}
+		if b == nil {
+			continue
+		}
+
+		delete(s.seekers, a.userName)
+		delete(s.seekers, b.userName)
+		g := &game{
+			user1:  a.userName,
+			user2:  b.userName,
+			boards: make(map[string]*board),
+		}
+		s.start(g, now)
+		// a finished game is kept under the name of its creator for the result to be confirmed
+		creator := a.userName
+		if _, ok := s.games[creator]; ok {
+			creator = b.userName
+		}
+		s.games[creator] = g
+		pairs = append(pairs, pairing{game: g, rating1: a.rating, rating2: b.rating})
+	}
+	return pairs
+}
+
+// announce tells the players of the quick matches who they play against
+func (s *Service) announce(pairs []pairing) {
+	for _, p := range pairs {
+		s.log.Info("quick match",
+			slog.String("user1", p.game.user1),
+			slog.String("user2", p.game.user2),
+			slog.Float64("rating_gap", math.Abs(p.rating1-p.rating2)),
+		)
+		s.notify(started(p.game, p.rating1, p.rating2)...)
+	}
+}
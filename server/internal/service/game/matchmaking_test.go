@@ -0,0 +1,190 @@
+package game
+
+import (
+	"battle-ship_server/internal/service/game/rating"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPairSeekers(t *testing.T) {
+	type queued struct {
+		login  string
+		rating float64
+		waited time.Duration
+		busy   bool // waits in a game of their own
+	}
+
+	tests := []struct {
+		name        string
+		matchmaking Matchmaking
+		queue       []queued
+		want        [][2]string // the longest waiting player first
+	}{
+		{
+			name:        "within the initial gap",
+			matchmaking: Matchmaking{InitialGap: 50},
+			queue:       []queued{{"alice", 1500, time.Second, false}, {"bob", 1540, 0, false}},
+			want:        [][2]string{{"alice", "bob"}},
+		},
+		{
+			name:        "gap too wide",
+			matchmaking: Matchmaking{InitialGap: 50, GapGrowth: 10},
+			queue:       []queued{{"alice", 1500, time.Second, false}, {"bob", 1600, 0, false}},
+		},
+		{
+			name:        "gap widened by waiting",
+			matchmaking: Matchmaking{InitialGap: 50, GapGrowth: 10},
+			queue:       []queued{{"alice", 1500, 10 * time.Second, false}, {"bob", 1600, 5 * time.Second, false}},
+			want:        [][2]string{{"alice", "bob"}},
+		},
+		{
+			name:        "the newcomer accepts no such gap yet",
+			matchmaking: Matchmaking{InitialGap: 50, GapGrowth: 10},
+			queue:       []queued{{"alice", 1500, time.Minute, false}, {"bob", 1600, time.Second, false}},
+		},
+		{
+			name:        "gap limited",
+			matchmaking: Matchmaking{InitialGap: 50, GapGrowth: 10, MaxGap: 80},
+			queue:       []queued{{"alice", 1500, time.Hour, false}, {"bob", 1600, time.Hour, false}},
+		},
+		{
+			name:        "closest rating",
+			matchmaking: Matchmaking{InitialGap: 50, GapGrowth: 10},
+			queue: []queued{
+				{"alice", 1500, 10 * time.Second, false},
+				{"bob", 1580, 5 * time.Second, false},
+				{"carol", 1520, 0, false},
+			},
+			want: [][2]string{{"alice", "carol"}},
+		},
+		{
+			name:        "busy seeker skipped",
+			matchmaking: Matchmaking{InitialGap: 50},
+			queue: []queued{
+				{"alice", 1500, 10 * time.Second, false},
+				{"bob", 1510, 5 * time.Second, true},
+				{"carol", 1520, 0, false},
+			},
+			want: [][2]string{{"alice", "carol"}},
+		},
+		{
+			name:        "longest waiting first",
+			matchmaking: Matchmaking{InitialGap: 50},
+			queue: []queued{
+				{"alice", 1500, 0, false},
+				{"bob", 1510, 10 * time.Second, false},
+				{"carol", 1505, 5 * time.Second, false},
+				{"dave", 1700, 0, false},
+			},
+			want: [][2]string{{"bob", "carol"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestService(Timeouts{}, tt.matchmaking)
+			now := baseTime.Add(time.Hour)
+			for _, q := range tt.queue {
+				s.seekers[q.login] = &seeker{userName: q.login, rating: q.rating, since: now.Add(-q.waited)}
+				if q.busy {
+					s.games[q.login] = &game{user1: q.login, status: wait, boards: make(map[string]*board)}
+				}
+			}
+
+			pairs := s.pairSeekers(now)
+
+			var got [][2]string
+			for _, p := range pairs {
+				got = append(got, [2]string{p.game.user1, p.game.user2})
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("pairs = %v, want %v", got, tt.want)
+			}
+			for _, q := range tt.queue {
+				paired := slices.ContainsFunc(tt.want, func(p [2]string) bool { return p[0] == q.login || p[1] == q.login })
+				if _, queued := s.seekers[q.login]; queued == paired {
+					t.Errorf("%s queued: %v, paired: %v", q.login, queued, paired)
+				}
+				if g := s.gameOf(q.login); (g != nil) != paired {
+					t.Errorf("%s has a running game: %v, paired: %v", q.login, g != nil, paired)
+				}
+			}
+		})
+	}
+}
+
+func TestQuickMatch(t *testing.T) {
+	s, storage := newTestService(Timeouts{Placement: time.Minute}, Matchmaking{InitialGap: 50})
+	storage.stats["bob"] = Statistics{Wins: 1, Rating: rating.Rating{Value: 1530}}
+
+	err := s.QuickMatch(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events := sentEvents(s); len(events) != 0 {
+		t.Fatalf("events before the opponent is found: %+v", events)
+	}
+	err = s.QuickMatch(context.Background(), "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := sentEvents(s)
+	alice, bob := eventTo(t, events, "alice"), eventTo(t, events, "bob")
+	if alice.Type != EventStarted || alice.Opponent != "bob" || alice.OpponentRating != 1530 {
+		t.Errorf("event to alice = %+v, want the start against bob rated 1530", alice)
+	}
+	if bob.Type != EventStarted || bob.Opponent != "alice" || bob.OpponentRating != 1500 {
+		t.Errorf("event to bob = %+v, want the start against alice rated 1500", bob)
+	}
+	if alice.GameID == "" || alice.GameID != bob.GameID {
+		t.Errorf("game ids %q and %q, want the same one", alice.GameID, bob.GameID)
+	}
+
+	if err := s.QuickMatch(context.Background(), "alice"); !errors.Is(err, ErrAlreadyPlaying) {
+		t.Errorf("QuickMatch while playing = %v, want %v", err, ErrAlreadyPlaying)
+	}
+	if err := s.CancelQuickMatch("alice"); !errors.Is(err, ErrNotQueued) {
+		t.Errorf("CancelQuickMatch after the pairing = %v, want %v", err, ErrNotQueued)
+	}
+}
+
+func TestQuickMatchLeftForAnotherGame(t *testing.T) {
+	s, _ := newTestService(Timeouts{Placement: time.Minute}, Matchmaking{InitialGap: 50})
+
+	err := s.QuickMatch(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.CreateGame("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CancelQuickMatch("alice"); !errors.Is(err, ErrNotQueued) {
+		t.Errorf("CancelQuickMatch after CreateGame = %v, want %v", err, ErrNotQueued)
+	}
+	if err := s.QuickMatch(context.Background(), "alice"); !errors.Is(err, ErrAlreadyPlaying) {
+		t.Errorf("QuickMatch while waiting in a game = %v, want %v", err, ErrAlreadyPlaying)
+	}
+}
+
+func TestPairSeekersKeepsFinishedGame(t *testing.T) {
+	s, _ := newTestService(Timeouts{}, Matchmaking{InitialGap: 50})
+	finishedGame := &game{user1: "alice", user2: "carol", status: finished, winner: "alice"}
+	s.games["alice"] = finishedGame
+	s.seekers["alice"] = &seeker{userName: "alice", rating: 1500, since: baseTime}
+	s.seekers["bob"] = &seeker{userName: "bob", rating: 1500, since: baseTime.Add(time.Second)}
+
+	pairs := s.pairSeekers(baseTime.Add(time.Minute))
+	if len(pairs) != 1 {
+		t.Fatalf("pairs = %d, want 1", len(pairs))
+	}
+	if s.games["alice"] != finishedGame {
+		t.Error("the finished game of alice is replaced")
+	}
+	if s.games["bob"] != pairs[0].game {
+		t.Error("the new game is not kept under the name of bob")
+	}
+}
@@ -0,0 +1,27 @@
+package rating
+
+import "math"
+
+// Elo is the classic Elo rating system with a constant K-factor
+type Elo struct {
+	initial float64
+	k       float64
+}
+
+func NewElo(initial, k float64) *Elo {
+	return &Elo{initial: initial, k: k}
+}
+
+func (e *Elo) Initial() Rating {
+	return Rating{Value: e.initial}
+}
+
+func (e *Elo) Rate(winner, loser Rating) (Rating, Rating) {
+	// expected score of the winner
+	expected := 1 / (1 + math.Pow(10, (loser.Value-winner.Value)/400))
+	delta := e.k * (1 - expected)
+
+	winner.Value += delta
+	loser.Value -= delta
+	return winner, loser
+}
@@ -0,0 +1,28 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+func TestElo(t *testing.T) {
+	tests := []struct {
+		name          string
+		winner, loser float64
+		wantWinner    float64
+		wantLoser     float64
+	}{
+		{"equal ratings", 1500, 1500, 1516, 1484},
+		{"favourite wins", 1800, 1400, 1800 + 32.0/11, 1400 - 32.0/11},
+		{"underdog wins", 1400, 1800, 1400 + 320.0/11, 1800 - 320.0/11},
+	}
+	elo := NewElo(1500, 32)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, l := elo.Rate(Rating{Value: tt.winner}, Rating{Value: tt.loser})
+			if math.Abs(w.Value-tt.wantWinner) > 1e-9 || math.Abs(l.Value-tt.wantLoser) > 1e-9 {
+				t.Errorf("Rate(%v, %v) = %v, %v, want %v, %v", tt.winner, tt.loser, w.Value, l.Value, tt.wantWinner, tt.wantLoser)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package rating
+
+import "math"
+
+// glicko2Scale converts ratings between the Glicko and the Glicko-2 scales
+const glicko2Scale = 173.7178
+
+// convergence tolerance of the volatility iteration
+const glicko2Epsilon = 0.000001
+
+// Glicko2 is the Glicko-2 rating system by Mark Glickman (http://www.glicko.net/glicko/glicko2.pdf).
+// Every game is treated as a separate rating period.
+type Glicko2 struct {
+	initial Rating
+	tau     float64 // constrains the change in volatility over time
+}
+
+func NewGlicko2(initial, deviation, volatility, tau float64) *Glicko2 {
+	return &Glicko2{
+		initial: Rating{Value: initial, Deviation: deviation, Volatility: volatility},
+		tau:     tau,
+	}
+}
+
+func (g *Glicko2) Initial() Rating {
+	return g.initial
+}
+
+func (g *Glicko2) Rate(winner, loser Rating) (Rating, Rating) {
+	return g.update(winner, loser, 1), g.update(loser, winner, 0)
+}
+
+// update returns the new rating of the player after a game with the given score against the opponent
+func (g *Glicko2) update(player, opponent Rating, score float64) Rating {
+	mu := (player.Value - 1500) / glicko2Scale
+	phi := player.Deviation / glicko2Scale
+	muJ := (opponent.Value - 1500) / glicko2Scale
+	phiJ := opponent.Deviation / glicko2Scale
+
+	gJ := 1 / math.Sqrt(1+3*phiJ*phiJ/(math.Pi*math.Pi))
+	e := 1 / (1 + math.Exp(-gJ*(mu-muJ)))
+	v := 1 / (gJ * gJ * e * (1 - e))
+	delta := v * gJ * (score - e)
+
+	sigma := g.volatility(phi, player.Volatility, v, delta)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*gJ*(score-e)
+
+	return Rating{
+		Value:      newMu*glicko2Scale + 1500,
+		Deviation:  newPhi * glicko2Scale,
+		Volatility: sigma,
+	}
+}
+
+// volatility finds the new volatility with the Illinois algorithm (step 5 of the paper)
+func (g *Glicko2) volatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	tau2 := g.tau * g.tau
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/tau2
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*g.tau) < 0 {
+			k++
+		}
+		B = a - k*g.tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glicko2Epsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
@@ -0,0 +1,57 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+// The expected ratings follow the steps of the paper for a rating period of a single game
+func TestGlicko2(t *testing.T) {
+	tests := []struct {
+		name          string
+		winner, loser Rating
+		wantWinner    Rating
+		wantLoser     Rating
+	}{
+		{
+			name:       "first opponent of the paper example",
+			winner:     Rating{1500, 200, 0.06},
+			loser:      Rating{1400, 30, 0.06},
+			wantWinner: Rating{1563.564, 175.403, 0.059999},
+			wantLoser:  Rating{1398.144, 31.670, 0.059999},
+		},
+		{
+			name:       "new players",
+			winner:     Rating{1500, 350, 0.06},
+			loser:      Rating{1500, 350, 0.06},
+			wantWinner: Rating{1662.311, 290.319, 0.060000},
+			wantLoser:  Rating{1337.689, 290.319, 0.060000},
+		},
+		{
+			name:       "underdog wins",
+			winner:     Rating{1400, 80, 0.06},
+			loser:      Rating{1700, 150, 0.06},
+			wantWinner: Rating{1427.263, 79.677, 0.060006},
+			wantLoser:  Rating{1602.867, 143.784, 0.060006},
+		},
+	}
+	glicko := NewGlicko2(1500, 350, 0.06, 0.5)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, l := glicko.Rate(tt.winner, tt.loser)
+			if !near(w, tt.wantWinner) {
+				t.Errorf("winner = %+v, want %+v", w, tt.wantWinner)
+			}
+			if !near(l, tt.wantLoser) {
+				t.Errorf("loser = %+v, want %+v", l, tt.wantLoser)
+			}
+		})
+	}
+}
+
+// near compares the ratings to the precision of the expected values
+func near(got, want Rating) bool {
+	return math.Abs(got.Value-want.Value) < 0.001 &&
+		math.Abs(got.Deviation-want.Deviation) < 0.001 &&
+		math.Abs(got.Volatility-want.Volatility) < 0.000001
+}
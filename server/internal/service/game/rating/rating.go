@@ -0,0 +1,18 @@
+// Package rating contains the rating systems used to rank players by their game results.
+package rating
+
+// Rating is the skill estimate of a player.
+// Deviation and Volatility are used only by the rating systems that track uncertainty.
+type Rating struct {
+	Value      float64
+	Deviation  float64
+	Volatility float64
+}
+
+// Calculator updates the ratings of two players after a game
+type Calculator interface {
+	// Initial returns the rating of a player who has not played yet
+	Initial() Rating
+	// Rate returns the new ratings of the winner and the loser of a game
+	Rate(winner, loser Rating) (newWinner, newLoser Rating)
+}
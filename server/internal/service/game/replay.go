@@ -0,0 +1,60 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+var ErrMatchNotFound = errors.New("match not found")
+
+type ReplayEventType string
+
+const (
+	ReplayFleet ReplayEventType = "fleet" // a player placed the fleet
+	ReplayShot  ReplayEventType = "shot"  // a shot was resolved
+	ReplayEnd   ReplayEventType = "end"   // the game is over
+)
+
+// ReplayEvent is an entry of the ordered log of a game
+type ReplayEvent struct {
+	Seq     int // starting from 0
+	Type    ReplayEventType
+	Player  string // ReplayFleet: the owner of the fleet, ReplayShot: the attacker, ReplayEnd: the winner
+	X       int    // ReplayShot
+	Y       int
+	Hit     bool
+	Destroy bool
+	Fleet   [][]Point // ReplayFleet
+	Reason  EndReason // ReplayEnd
+	At      time.Time
+}
+
+// record appends the event to the log of the game. Must be called with s.mu held.
+func (g *game) record(e ReplayEvent) {
+	e.Seq = len(g.replay)
+	e.At = time.Now()
+	g.replay = append(g.replay, e)
+}
+
+// GetReplay returns the finished game with its log of events
+func (s *Service) GetReplay(ctx context.Context, matchID int64) (Match, error) {
+	const op = "Service.GetReplay"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("match_id", matchID),
+	)
+
+	match, err := s.Storage.GetReplay(ctx, matchID)
+	if errors.Is(err, ErrMatchNotFound) {
+		return Match{}, err
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return Match{}, err
+	}
+
+	return match, nil
+}
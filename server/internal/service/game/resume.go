@@ -0,0 +1,55 @@
+package game
+
+import (
+	"log/slog"
+	"time"
+)
+
+// GameState is what a player who reconnects needs to continue the running game
+type GameState struct {
+	Opponent      string
+	FleetPlaced   bool      // the user has placed the fleet
+	Started       bool      // both fleets are placed
+	MyTurn        bool      // the user shoots next
+	Fleet         [][]Point // the ships of the user, nil if not placed yet
+	MyShots       []Shot    // shots of the user at the opponent's sea
+	OpponentShots []Shot    // shots of the opponent at the user's sea
+}
+
+// Resume returns the state of the running game of the user. The game waits for the user
+// to come back for the heartbeat timeout, after that it is forfeited.
+func (s *Service) Resume(userName string) (GameState, error) {
+	const op = "Service.Resume"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_name", userName),
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g := s.gameOf(userName)
+	if g == nil {
+		return GameState{}, ErrGameNotFound
+	}
+	g.lastSeen[userName] = time.Now()
+
+	opponent := g.opponent(userName)
+	state := GameState{
+		Opponent: opponent,
+		Started:  g.turn != "",
+		MyTurn:   g.turn == userName,
+	}
+	if b := g.boards[userName]; b != nil {
+		state.FleetPlaced = true
+		state.Fleet = b.fleet()
+		state.OpponentShots = b.resolvedShots()
+	}
+	if b := g.boards[opponent]; b != nil {
+		state.MyShots = b.resolvedShots()
+	}
+
+	log.Info("game resumed", slog.String("opponent", opponent), slog.Bool("started", state.Started))
+	return state, nil
+}
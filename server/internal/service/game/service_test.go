@@ -0,0 +1,90 @@
+package game
+
+import (
+	"battle-ship_server/internal/service/game/rating"
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// baseTime is the start of the controlled clock of the tests
+var baseTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// statStorage keeps the statistics and the matches in memory, the storage drivers import the service
+// and can't be used here. The methods the tests don't need are left to the embedded nil interface.
+type statStorage struct {
+	StatStorage
+	mu      sync.Mutex
+	stats   map[string]Statistics
+	matches []Match
+}
+
+func (s *statStorage) GetStat(_ context.Context, login string) (Statistics, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats[login], nil
+}
+
+func (s *statStorage) RecordResult(_ context.Context, match Match, apply ApplyResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	winner, loser := s.stats[match.Winner], s.stats[match.Loser]
+	apply(&winner, &loser, &match)
+	s.stats[match.Winner], s.stats[match.Loser] = winner, loser
+	s.matches = append(s.matches, match)
+	return nil
+}
+
+// newTestService returns the service without the matchmaker and the watchdog,
+// the tests run them on their own clock
+func newTestService(timeouts Timeouts, matchmaking Matchmaking) (*Service, *statStorage) {
+	storage := &statStorage{stats: make(map[string]Statistics)}
+	return &Service{
+		Storage:     storage,
+		log:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		rating:      rating.NewElo(1500, 32),
+		games:       make(map[string]*game),
+		events:      make(chan Event, 1024),
+		timeouts:    timeouts,
+		matchmaking: matchmaking,
+		seekers:     make(map[string]*seeker),
+		done:        make(chan struct{}),
+	}, storage
+}
+
+// sentEvents returns the events sent so far
+func sentEvents(s *Service) []Event {
+	var events []Event
+	for {
+		select {
+		case e := <-s.events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+// startedGame starts the game of alice and bob at the time
+func startedGame(s *Service, now time.Time) *game {
+	g := &game{user1: "alice", user2: "bob", boards: make(map[string]*board)}
+	s.start(g, now)
+	s.games["alice"] = g
+	return g
+}
+
+// eventTo returns the event sent to the user, it fails the test if there is none
+func eventTo(t *testing.T, events []Event, to string) Event {
+	t.Helper()
+	for _, e := range events {
+		if e.To == to {
+			return e
+		}
+	}
+	t.Fatalf("no event to %q in %+v", to, events)
+	return Event{}
+}
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"sort"
+	"time"
+)
+
+// LiveGame is a running game that can be watched
+type LiveGame struct {
+	ID        string
+	User1     string
+	User2     string
+	Started   bool // both fleets are placed
+	Shots     int
+	StartedAt time.Time
+}
+
+// SpectatorView is what a spectator sees of the running game: the shots made so far, but not the ships
+type SpectatorView struct {
+	LiveGame
+	Turn     string            // the player who shoots next
+	SeaShots map[string][]Shot // user name -> shots at the sea of the user
+}
+
+// LiveGames returns the running games, the latest started first
+func (s *Service) LiveGames() []LiveGame {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	games := make([]LiveGame, 0)
+	for _, g := range s.games {
+		if g.status == inProgress {
+			games = append(games, g.live())
+		}
+	}
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].StartedAt.After(games[j].StartedAt)
+	})
+	return games
+}
+
+// Watch returns the current state of the running game for a spectator who has just subscribed to its events
+func (s *Service) Watch(gameID string) (SpectatorView, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, g := range s.games {
+		if g.id != gameID || g.status != inProgress {
+			continue
+		}
+		view := SpectatorView{
+			LiveGame: g.live(),
+			Turn:     g.turn,
+			SeaShots: make(map[string][]Shot, len(g.boards)),
+		}
+		for user, b := range g.boards {
+			view.SeaShots[user] = b.resolvedShots()
+		}
+		return view, nil
+	}
+	return SpectatorView{}, ErrGameNotFound
+}
+
+// live describes the running game. Must be called with s.mu held.
+func (g *game) live() LiveGame {
+	return LiveGame{
+		ID:        g.id,
+		User1:     g.user1,
+		User2:     g.user2,
+		Started:   g.turn != "",
+		Shots:     g.shots,
+		StartedAt: g.startedAt,
+	}
+}
@@ -0,0 +1,108 @@
+package game
+
+import (
+	"battle-ship_server/internal/service/game/rating"
+	"context"
+	"log/slog"
+)
+
+// currentRating returns the rating of the player, players without games get the initial rating of the rating system.
+// The ratings saved by a system without the uncertainty, e.g. Elo, get the initial deviation and volatility
+// until RecomputeRatings is run, Glicko-2 can't rate with zeros.
+func (s *Service) currentRating(stat Statistics) rating.Rating {
+	initial := s.rating.Initial()
+	if stat.Wins+stat.Losses == 0 {
+		return initial
+	}
+	r := stat.Rating
+	if r.Deviation == 0 || r.Volatility == 0 {
+		r.Deviation, r.Volatility = initial.Deviation, initial.Volatility
+	}
+	return r
+}
+
+// RecomputeRatings resets the ratings of all players and replays all recorded results with the current rating system.
+// Wins and losses are kept. It is meant to be run once while the server is stopped, e.g. after changing the rating system.
+func (s *Service) RecomputeRatings(ctx context.Context) error {
+	const op = "Service.RecomputeRatings"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	results, err := s.Storage.GetResults(ctx)
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	ratings := make(map[string]rating.Rating)
+	get := func(login string) rating.Rating {
+		if r, ok := ratings[login]; ok {
+			return r
+		}
+		return s.rating.Initial()
+	}
+	for _, res := range results {
+		ratings[res.Winner], ratings[res.Loser] = s.rating.Rate(get(res.Winner), get(res.Loser))
+	}
+
+	err = s.Storage.ResetRatings(ctx, s.rating.Initial())
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	for login, r := range ratings {
+		stat, err := s.Storage.GetStat(ctx, login)
+		if err != nil {
+			log.Error(err.Error(), slog.String("login", login))
+			return err
+		}
+		stat.Rating = r
+		err = s.Storage.UpdateStat(ctx, login, stat)
+		if err != nil {
+			log.Error(err.Error(), slog.String("login", login))
+			return err
+		}
+	}
+
+	log.Info("ratings recomputed", slog.Int("results", len(results)), slog.Int("players", len(ratings)))
+	return nil
+}
+
+// LeaderboardEntry is a row of the leaderboard
+type LeaderboardEntry struct {
+	Rank  int // starting from 1
+	Login string
+	Stat  Statistics
+}
+
+const maxLeaderboardPageSize = 100
+
+// GetLeaderboard returns a page of the leaderboard, the number of ranked players and the rank of the user
+func (s *Service) GetLeaderboard(ctx context.Context, userName string, page, pageSize int) (entries []LeaderboardEntry, total int, userRank int, err error) {
+	const op = "Service.GetLeaderboard"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_name", userName),
+	)
+
+	page = max(page, 1)
+	pageSize = min(max(pageSize, 1), maxLeaderboardPageSize)
+
+	entries, total, err = s.Storage.GetLeaderboard(ctx, pageSize, (page-1)*pageSize)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, 0, 0, err
+	}
+
+	userRank, err = s.Storage.GetRank(ctx, userName)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, 0, 0, err
+	}
+
+	return entries, total, userRank, nil
+}
@@ -0,0 +1,127 @@
+package game
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Timeouts sets how long the server waits for the players of a running game
+type Timeouts struct {
+	Heartbeat time.Duration // a player silent for longer has abandoned the game, it is also the time to reconnect and resume
+	Placement time.Duration // both fleets must be placed within it after the game starts
+	Turn      time.Duration // the player must shoot within it when the turn comes
+}
+
+const (
+	watchInterval   = time.Second
+	finishedGameTTL = 5 * time.Minute // finished games are kept for the players to confirm the result
+)
+
+// Heartbeat marks the user as still present in the running game
+func (s *Service) Heartbeat(userName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g := s.gameOf(userName)
+	if g == nil {
+		return ErrGameNotFound
+	}
+	g.lastSeen[userName] = time.Now()
+	return nil
+}
+
+// watchdog ends the games whose players went silent or missed the deadline until the service is closed
+func (s *Service) watchdog() {
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.checkGames(now)
+		}
+	}
+}
+
+// checkGames forfeits the games of the players who went silent or missed the deadline
+// and removes the games finished long ago
+func (s *Service) checkGames(now time.Time) {
+	const op = "Service.checkGames"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	var (
+		matches []Match
+		events  []Event
+	)
+
+	s.mu.Lock()
+	for creator, g := range s.games {
+		switch g.status {
+		case finished:
+			if now.Sub(g.endedAt) > finishedGameTTL {
+				delete(s.games, creator)
+			}
+			continue
+		case wait:
+			continue
+		}
+
+		var silent []string
+		for _, user := range []string{g.user1, g.user2} {
+			if now.Sub(g.lastSeen[user]) > s.timeouts.Heartbeat {
+				silent = append(silent, user)
+			}
+		}
+
+		switch {
+		case len(silent) == 2:
+			delete(s.games, creator)
+			events = append(events, Event{Type: EventEnd, GameID: g.id, Reason: EndAbandoned, Fleets: g.fleets()})
+			log.Info("both players left the game", slog.String("user1", g.user1), slog.String("user2", g.user2))
+		case len(silent) == 1:
+			match, e := s.forfeit(g, silent[0], EndAbandoned)
+			matches, events = append(matches, match), append(events, e...)
+			log.Info("game abandoned", slog.String("loser", silent[0]))
+		case now.After(g.deadline) && g.turn != "":
+			loser := g.turn
+			match, e := s.forfeit(g, loser, EndTimeout)
+			matches, events = append(matches, match), append(events, e...)
+			log.Info("turn timed out", slog.String("loser", loser))
+		case now.After(g.deadline):
+			var late []string
+			for _, user := range []string{g.user1, g.user2} {
+				if g.boards[user] == nil {
+					late = append(late, user)
+				}
+			}
+			if len(late) == 1 {
+				match, e := s.forfeit(g, late[0], EndTimeout)
+				matches, events = append(matches, match), append(events, e...)
+				log.Info("fleet placement timed out", slog.String("loser", late[0]))
+				continue
+			}
+			delete(s.games, creator)
+			events = append(events,
+				Event{Type: EventEnd, To: g.user1, GameID: g.id, Reason: EndCancelled},
+				Event{Type: EventEnd, To: g.user2, GameID: g.id, Reason: EndCancelled},
+				Event{Type: EventEnd, GameID: g.id, Reason: EndCancelled, Fleets: g.fleets()},
+			)
+			log.Info("game cancelled, no fleet placed in time", slog.String("user1", g.user1), slog.String("user2", g.user2))
+		}
+	}
+	s.mu.Unlock()
+
+	s.notify(events...)
+	for _, match := range matches {
+		err := s.recordResult(context.Background(), match)
+		if err != nil {
+			log.Error("failed to record the result", slog.String("error", err.Error()))
+		}
+	}
+}
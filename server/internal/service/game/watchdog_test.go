@@ -0,0 +1,167 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckGames(t *testing.T) {
+	timeouts := Timeouts{Heartbeat: 30 * time.Second, Placement: time.Minute, Turn: 20 * time.Second}
+
+	tests := []struct {
+		name string
+		// setup prepares the game started at baseTime and returns the time to check it at
+		setup      func(g *game) time.Time
+		wantKept   bool      // the game is still kept by the service
+		wantReason EndReason // empty if the game goes on
+		wantWinner string    // the result is recorded if set
+		wantGone   bool      // both players are gone, only the spectators are told
+	}{
+		{
+			name: "players present before the deadline",
+			setup: func(g *game) time.Time {
+				return seen(g, baseTime.Add(50*time.Second), "alice", "bob")
+			},
+			wantKept: true,
+		},
+		{
+			name: "one player silent",
+			setup: func(g *game) time.Time {
+				return seen(g, baseTime.Add(31*time.Second), "alice")
+			},
+			wantKept:   true,
+			wantReason: EndAbandoned,
+			wantWinner: "alice",
+		},
+		{
+			name: "both players silent",
+			setup: func(g *game) time.Time {
+				return baseTime.Add(31 * time.Second)
+			},
+			wantReason: EndAbandoned,
+			wantGone:   true,
+		},
+		{
+			name: "turn timed out",
+			setup: func(g *game) time.Time {
+				placed(g, "alice", "bob")
+				g.turn = "bob"
+				g.deadline = baseTime.Add(10 * time.Second)
+				return seen(g, baseTime.Add(11*time.Second), "alice", "bob")
+			},
+			wantKept:   true,
+			wantReason: EndTimeout,
+			wantWinner: "alice",
+		},
+		{
+			name: "turn before the deadline",
+			setup: func(g *game) time.Time {
+				placed(g, "alice", "bob")
+				g.turn = "bob"
+				g.deadline = baseTime.Add(10 * time.Second)
+				return seen(g, baseTime.Add(9*time.Second), "alice", "bob")
+			},
+			wantKept: true,
+		},
+		{
+			name: "fleet placement timed out",
+			setup: func(g *game) time.Time {
+				placed(g, "bob")
+				return seen(g, baseTime.Add(61*time.Second), "alice", "bob")
+			},
+			wantKept:   true,
+			wantReason: EndTimeout,
+			wantWinner: "bob",
+		},
+		{
+			name: "no fleet placed in time",
+			setup: func(g *game) time.Time {
+				return seen(g, baseTime.Add(61*time.Second), "alice", "bob")
+			},
+			wantReason: EndCancelled,
+		},
+		{
+			name: "finished game kept for the result",
+			setup: func(g *game) time.Time {
+				g.status, g.winner, g.endedAt = finished, "alice", baseTime
+				return baseTime.Add(finishedGameTTL)
+			},
+			wantKept: true,
+		},
+		{
+			name: "finished game removed",
+			setup: func(g *game) time.Time {
+				g.status, g.winner, g.endedAt = finished, "alice", baseTime
+				return baseTime.Add(finishedGameTTL + time.Second)
+			},
+		},
+		{
+			name: "waiting game kept",
+			setup: func(g *game) time.Time {
+				g.status, g.user2 = wait, ""
+				return baseTime.Add(time.Hour)
+			},
+			wantKept: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, storage := newTestService(timeouts, Matchmaking{})
+			g := startedGame(s, baseTime)
+			now := tt.setup(g)
+
+			s.checkGames(now)
+
+			if _, kept := s.games["alice"]; kept != tt.wantKept {
+				t.Errorf("game kept: %v, want %v", kept, tt.wantKept)
+			}
+
+			events := sentEvents(s)
+			if tt.wantReason == "" {
+				if len(events) != 0 {
+					t.Errorf("events of a game that goes on: %+v", events)
+				}
+				return
+			}
+			switch {
+			case tt.wantGone && (len(events) != 1 || events[0].To != ""):
+				t.Fatalf("events = %+v, want one to the spectators", events)
+			case !tt.wantGone && len(events) != 3:
+				t.Fatalf("events = %+v, want one to each player and one to the spectators", events)
+			}
+			for _, e := range events {
+				if e.Type != EventEnd || e.Reason != tt.wantReason || e.Winner != tt.wantWinner {
+					t.Errorf("event = %+v, want the end by %q won by %q", e, tt.wantReason, tt.wantWinner)
+				}
+			}
+
+			if tt.wantWinner == "" {
+				if len(storage.matches) != 0 {
+					t.Errorf("matches = %+v, want none recorded", storage.matches)
+				}
+				return
+			}
+			if len(storage.matches) != 1 || storage.matches[0].Winner != tt.wantWinner || storage.matches[0].EndReason != tt.wantReason {
+				t.Errorf("matches = %+v, want the win of %s by %q", storage.matches, tt.wantWinner, tt.wantReason)
+			}
+			if g.status != finished {
+				t.Errorf("status = %v, want finished", g.status)
+			}
+		})
+	}
+}
+
+// seen marks the players as present at the time and returns it
+func seen(g *game, now time.Time, users ...string) time.Time {
+	for _, user := range users {
+		g.lastSeen[user] = now
+	}
+	return now
+}
+
+// placed gives the players empty boards, the watchdog only checks that the fleets are placed
+func placed(g *game, users ...string) {
+	for _, user := range users {
+		g.boards[user] = &board{}
+	}
+}
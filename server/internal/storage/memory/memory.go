@@ -0,0 +1,230 @@
+// Package memory keeps the users, the statistics and the matches in memory.
+// It behaves like the postgres storage and is meant for tests and local play, everything is lost on exit.
+package memory
+
+import (
+	"battle-ship_server/internal/service/game"
+	"battle-ship_server/internal/service/game/rating"
+	"battle-ship_server/internal/storage"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// the defaults of a new player, the same as the column defaults of the postgres storage
+var initialStat = game.Statistics{
+	Rating: rating.Rating{Value: 1500, Deviation: 350, Volatility: 0.06},
+}
+
+type Storage struct {
+	mu        sync.RWMutex
+	passwords map[string][]byte // login -> password hash
+	stats     map[string]game.Statistics
+	matches   []game.Match // in the order they were saved, the ID is the position + 1
+}
+
+func New() *Storage {
+	return &Storage{
+		passwords: make(map[string][]byte),
+		stats:     make(map[string]game.Statistics),
+	}
+}
+
+func (s *Storage) SaveUser(_ context.Context, login string, passHash []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.passwords[login]; ok {
+		return storage.ErrUserExists
+	}
+	s.passwords[login] = slices.Clone(passHash)
+	s.stats[login] = initialStat
+
+	return nil
+}
+
+func (s *Storage) GetUserData(_ context.Context, login string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	passHash, ok := s.passwords[login]
+	if !ok {
+		return nil, storage.ErrUserNotFound
+	}
+
+	return slices.Clone(passHash), nil
+}
+
+func (s *Storage) UpdateStat(_ context.Context, login string, stat game.Statistics) error {
+	const op = "storage.memory.UpdateStat"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the statistics of an unknown user break the foreign key in postgres
+	if _, ok := s.passwords[login]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+	s.stats[login] = stat
+
+	return nil
+}
+
+func (s *Storage) GetStat(_ context.Context, login string) (game.Statistics, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stat, ok := s.stats[login]
+	if !ok {
+		return game.Statistics{}, storage.ErrUserNotFound
+	}
+
+	return stat, nil
+}
+
+func (s *Storage) RecordResult(_ context.Context, match game.Match, apply game.ApplyResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	winnerStat, ok1 := s.stats[match.Winner]
+	loserStat, ok2 := s.stats[match.Loser]
+	if !ok1 || !ok2 {
+		return storage.ErrUserNotFound
+	}
+
+	apply(&winnerStat, &loserStat, &match)
+
+	s.stats[match.Winner], s.stats[match.Loser] = winnerStat, loserStat
+	match.ID = int64(len(s.matches) + 1)
+	match.Events = slices.Clone(match.Events)
+	s.matches = append(s.matches, match)
+
+	return nil
+}
+
+func (s *Storage) GetReplay(_ context.Context, matchID int64) (game.Match, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if matchID < 1 || matchID > int64(len(s.matches)) {
+		return game.Match{}, game.ErrMatchNotFound
+	}
+	m := s.matches[matchID-1]
+	m.Events = slices.Clone(m.Events)
+
+	return m, nil
+}
+
+func (s *Storage) GetMatches(_ context.Context, login string, limit, offset int) ([]game.Match, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := make([]game.Match, 0)
+	for _, m := range s.matches {
+		if m.Winner == login || m.Loser == login {
+			m.Events = nil // the history is listed without the events
+			matches = append(matches, m)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if !matches[i].EndedAt.Equal(matches[j].EndedAt) {
+			return matches[i].EndedAt.After(matches[j].EndedAt)
+		}
+		return matches[i].ID > matches[j].ID
+	})
+
+	return page(matches, limit, offset), len(matches), nil
+}
+
+func (s *Storage) GetResults(_ context.Context) ([]game.Result, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := slices.Clone(s.matches)
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].EndedAt.Before(matches[j].EndedAt)
+	})
+
+	results := make([]game.Result, 0, len(matches))
+	for _, m := range matches {
+		results = append(results, game.Result{Winner: m.Winner, Loser: m.Loser})
+	}
+
+	return results, nil
+}
+
+func (s *Storage) ResetRatings(_ context.Context, initial rating.Rating) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for login, stat := range s.stats {
+		stat.Rating = initial
+		s.stats[login] = stat
+	}
+
+	return nil
+}
+
+func (s *Storage) GetLeaderboard(_ context.Context, limit, offset int) ([]game.LeaderboardEntry, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := s.ranked()
+	return page(entries, limit, offset), len(entries), nil
+}
+
+func (s *Storage) GetRank(_ context.Context, login string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, e := range s.ranked() {
+		if e.Login == login {
+			return e.Rank, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (s *Storage) Close() {}
+
+// ranked returns the players who have played at least once in the order of the leaderboard.
+// Must be called with s.mu held.
+func (s *Storage) ranked() []game.LeaderboardEntry {
+	entries := make([]game.LeaderboardEntry, 0, len(s.stats))
+	for login, stat := range s.stats {
+		if stat.Wins+stat.Losses > 0 {
+			entries = append(entries, game.LeaderboardEntry{Login: login, Stat: stat})
+		}
+	}
+
+	// rating, then wins and losses, the login makes the order of equal players deterministic
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch {
+		case a.Stat.Rating.Value != b.Stat.Rating.Value:
+			return a.Stat.Rating.Value > b.Stat.Rating.Value
+		case a.Stat.Wins != b.Stat.Wins:
+			return a.Stat.Wins > b.Stat.Wins
+		case a.Stat.Losses != b.Stat.Losses:
+			return a.Stat.Losses < b.Stat.Losses
+		default:
+			return a.Login < b.Login
+		}
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+
+	return entries
+}
+
+// page returns the part of the items a LIMIT/OFFSET query would return
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	return items[offset:min(offset+limit, len(items))]
+}
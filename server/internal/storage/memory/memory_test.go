@@ -0,0 +1,12 @@
+package memory
+
+import (
+	"battle-ship_server/internal/storage/storagetest"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		return New()
+	})
+}
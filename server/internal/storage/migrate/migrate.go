@@ -0,0 +1,191 @@
+// Package migrate applies and rolls back the schema migrations shipped with the storage drivers.
+// The drivers embed their migrations and run them on their databases through DB.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrSchemaTooNew is returned when the database was migrated by a newer server than this one
+var ErrSchemaTooNew = errors.New("database schema is newer than the server supports")
+
+// Migration is a schema change shipped with the server. The files of the migration are named
+// NNNN_name.up.sql and NNNN_name.down.sql, where NNNN is the version the migration brings the schema to.
+type Migration struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time // zero if the migration is not applied
+	up        string
+	down      string
+}
+
+// DB is the database of a storage driver, it keeps the applied migrations in a table of its own
+type DB interface {
+	// Applied returns the versions of the applied migrations with the times they were applied at
+	Applied(ctx context.Context) (map[int]time.Time, error)
+	// Apply runs the script and records the migration as applied, or as rolled back, in a single transaction
+	Apply(ctx context.Context, script string, version int, name string, up bool) error
+	// Lock waits for the other servers to finish migrating the schema
+	Lock(ctx context.Context) (unlock func(), err error)
+}
+
+// Migrator applies and rolls back the migrations of a storage driver
+type Migrator struct {
+	db         DB
+	migrations []Migration
+}
+
+// New reads the migrations from the migrations directory of fsys
+func New(db DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// load reads the migrations ordered by version
+func load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "migrations/*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(files))
+	for _, file := range files {
+		base := strings.TrimSuffix(path.Base(file), ".up.sql")
+		num, name, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(num)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("bad migration file name %q", file)
+		}
+
+		up, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		down, err := fs.ReadFile(fsys, path.Join("migrations", base+".down.sql"))
+		if err != nil {
+			return nil, fmt.Errorf("migration %d has no down file: %w", version, err)
+		}
+
+		migrations = append(migrations, Migration{Version: version, Name: name, up: string(up), down: string(down)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %d is missing", i+1)
+		}
+	}
+
+	return migrations, nil
+}
+
+// Latest returns the version of the newest migration known to the server
+func (m *Migrator) Latest() int {
+	return len(m.migrations)
+}
+
+// Version returns the version of the database schema, 0 if nothing is applied
+func (m *Migrator) Version() (int, error) {
+	const op = "storage.migrate.Version"
+
+	applied, err := m.db.Applied(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	version := 0
+	for v := range applied {
+		version = max(version, v)
+	}
+	return version, nil
+}
+
+// Status returns all known migrations with the time they were applied at
+func (m *Migrator) Status() ([]Migration, error) {
+	const op = "storage.migrate.Status"
+
+	applied, err := m.db.Applied(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	status := make([]Migration, len(m.migrations))
+	copy(status, m.migrations)
+	for i := range status {
+		status[i].AppliedAt = applied[status[i].Version]
+	}
+
+	return status, nil
+}
+
+// Up applies all pending migrations and returns the number of them.
+// It fails with ErrSchemaTooNew if the database has migrations unknown to the server.
+func (m *Migrator) Up() (int, error) {
+	const op = "storage.migrate.Up"
+
+	unlock, err := m.db.Lock(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer unlock()
+
+	version, err := m.Version()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if version > m.Latest() {
+		return 0, fmt.Errorf("%s: %w: database is at %d, the server knows up to %d", op, ErrSchemaTooNew, version, m.Latest())
+	}
+
+	for _, mg := range m.migrations[version:] {
+		err = m.db.Apply(context.Background(), mg.up, mg.Version, mg.Name, true)
+		if err != nil {
+			return mg.Version - version - 1, fmt.Errorf("%s: migration %d_%s: %w", op, mg.Version, mg.Name, err)
+		}
+	}
+
+	return m.Latest() - version, nil
+}
+
+// Down rolls back the given number of the latest applied migrations and returns the number of them
+func (m *Migrator) Down(steps int) (int, error) {
+	const op = "storage.migrate.Down"
+
+	unlock, err := m.db.Lock(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer unlock()
+
+	version, err := m.Version()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if version > m.Latest() {
+		return 0, fmt.Errorf("%s: %w: database is at %d, the server knows up to %d", op, ErrSchemaTooNew, version, m.Latest())
+	}
+
+	done := 0
+	for ; done < steps && version > 0; done, version = done+1, version-1 {
+		mg := m.migrations[version-1]
+		err = m.db.Apply(context.Background(), mg.down, mg.Version, mg.Name, false)
+		if err != nil {
+			return done, fmt.Errorf("%s: migration %d_%s: %w", op, mg.Version, mg.Name, err)
+		}
+	}
+
+	return done, nil
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"battle-ship_server/internal/storage/migrate"
+	"context"
+	"embed"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrSchemaTooNew is returned when the database was migrated by a newer server than this one
+var ErrSchemaTooNew = migrate.ErrSchemaTooNew
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationsLock is the advisory lock key held while the schema is migrated,
+// so that two servers started at once do not apply the same migration twice
+const migrationsLock = 4242_0001
+
+// Migrator applies and rolls back the migrations of the postgres storage, the applied ones are kept in schema_migrations
+type Migrator struct {
+	*migrate.Migrator
+	db *pgx.Conn
+}
+
+// NewMigrator connects to the database to migrate its schema
+func NewMigrator(storagePath string) (*Migrator, error) {
+	const op = "storage.postgres.NewMigrator"
+
+	db, err := pgx.Connect(context.Background(), storagePath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = db.Exec(context.Background(), `
+		CREATE TABLE IF NOT EXISTS schema_migrations(
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+	`)
+	if err != nil {
+		db.Close(context.Background())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	m, err := migrate.New(migrationDB{db: db}, migrationsFS)
+	if err != nil {
+		db.Close(context.Background())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Migrator{Migrator: m, db: db}, nil
+}
+
+func (m *Migrator) Close() {
+	m.db.Close(context.Background())
+}
+
+// migrationDB runs the migrations on the connection
+type migrationDB struct {
+	db *pgx.Conn
+}
+
+func (m migrationDB) Applied(ctx context.Context) (map[int]time.Time, error) {
+	rows, err := m.db.Query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]time.Time)
+	var (
+		version int
+		at      time.Time
+	)
+	_, err = pgx.ForEachRow(rows, []any{&version, &at}, func() error {
+		applied[version] = at
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+func (m migrationDB) Apply(ctx context.Context, script string, version int, name string, up bool) error {
+	return pgx.BeginFunc(ctx, m.db, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, script)
+		if err != nil {
+			return err
+		}
+		if up {
+			_, err = tx.Exec(ctx, `INSERT INTO schema_migrations(version, name) VALUES ($1, $2)`, version, name)
+		} else {
+			_, err = tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, version)
+		}
+		return err
+	})
+}
+
+func (m migrationDB) Lock(ctx context.Context) (unlock func(), err error) {
+	_, err = m.db.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationsLock)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		m.db.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationsLock)
+	}, nil
+}
@@ -0,0 +1,77 @@
+-- The schema created by the server before the migrations were introduced.
+-- Every statement is idempotent, so the migration is also applied to such databases.
+
+CREATE TABLE IF NOT EXISTS users(
+    id SERIAL PRIMARY KEY,
+    login TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_users_login ON users(login);
+
+CREATE TABLE IF NOT EXISTS players_statistics(
+    id SERIAL PRIMARY KEY,
+    user_login TEXT REFERENCES users(login),
+    wins INTEGER NOT NULL DEFAULT 0,
+    losses INTEGER NOT NULL DEFAULT 0,
+    rating DOUBLE PRECISION NOT NULL DEFAULT 1500,
+    rating_deviation DOUBLE PRECISION NOT NULL DEFAULT 350,
+    rating_volatility DOUBLE PRECISION NOT NULL DEFAULT 0.06
+);
+CREATE INDEX IF NOT EXISTS idx_player_statistics_user_login ON players_statistics(user_login);
+-- ratings were integers before the rating systems were introduced
+ALTER TABLE players_statistics
+    ALTER COLUMN rating TYPE DOUBLE PRECISION,
+    ALTER COLUMN rating SET DEFAULT 1500,
+    ADD COLUMN IF NOT EXISTS rating_deviation DOUBLE PRECISION NOT NULL DEFAULT 350,
+    ADD COLUMN IF NOT EXISTS rating_volatility DOUBLE PRECISION NOT NULL DEFAULT 0.06;
+CREATE INDEX IF NOT EXISTS idx_player_statistics_rating ON players_statistics(rating DESC, wins DESC, losses ASC, user_login ASC);
+
+CREATE TABLE IF NOT EXISTS matches(
+    id SERIAL PRIMARY KEY,
+    winner_login TEXT NOT NULL REFERENCES users(login),
+    loser_login TEXT NOT NULL REFERENCES users(login),
+    started_at TIMESTAMPTZ,
+    ended_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    shots INTEGER NOT NULL DEFAULT 0,
+    winner_rating_delta DOUBLE PRECISION NOT NULL DEFAULT 0,
+    loser_rating_delta DOUBLE PRECISION NOT NULL DEFAULT 0,
+    end_reason TEXT NOT NULL DEFAULT ''
+);
+-- matches kept only the players before the match history was introduced
+ALTER TABLE matches
+    ADD COLUMN IF NOT EXISTS started_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS shots INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS winner_rating_delta DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS loser_rating_delta DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS end_reason TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_matches_winner_login ON matches(winner_login);
+CREATE INDEX IF NOT EXISTS idx_matches_loser_login ON matches(loser_login);
+
+CREATE TABLE IF NOT EXISTS match_events(
+    match_id INTEGER NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    seq INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    player TEXT NOT NULL,
+    x INTEGER NOT NULL DEFAULT 0,
+    y INTEGER NOT NULL DEFAULT 0,
+    hit BOOLEAN NOT NULL DEFAULT false,
+    destroy BOOLEAN NOT NULL DEFAULT false,
+    fleet JSONB,
+    reason TEXT NOT NULL DEFAULT '',
+    at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (match_id, seq)
+);
+
+CREATE OR REPLACE FUNCTION create_player_statistics() RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO players_statistics(user_login)
+  VALUES (NEW.login);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS create_player_statistics_trigger ON users;
+CREATE TRIGGER create_player_statistics_trigger
+AFTER INSERT ON users
+FOR EACH ROW
+EXECUTE FUNCTION create_player_statistics();
@@ -0,0 +1,2 @@
+ALTER TABLE players_statistics DROP CONSTRAINT IF EXISTS players_statistics_user_login_key;
+CREATE INDEX IF NOT EXISTS idx_player_statistics_user_login ON players_statistics(user_login);
@@ -0,0 +1,11 @@
+-- updateStat upserts ON CONFLICT (user_login), which needs a unique constraint to match.
+-- Only the row with the most games of every player is kept, the first created one on a tie.
+DELETE FROM players_statistics a
+USING players_statistics b
+WHERE a.user_login = b.user_login
+  AND (a.wins + a.losses < b.wins + b.losses
+    OR (a.wins + a.losses = b.wins + b.losses AND a.id > b.id));
+
+-- the unique constraint brings its own index
+DROP INDEX IF EXISTS idx_player_statistics_user_login;
+ALTER TABLE players_statistics ADD CONSTRAINT players_statistics_user_login_key UNIQUE (user_login);
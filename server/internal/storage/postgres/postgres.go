@@ -2,132 +2,191 @@ package postgres
 
 import (
 	"battle-ship_server/internal/service/game"
+	"battle-ship_server/internal/service/game/rating"
 	"battle-ship_server/internal/storage"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Storage struct {
-	db *pgx.Conn
+	db           *pgxpool.Pool
+	queryTimeout time.Duration
 }
 
-// Prepared statements names
+// PoolConfig sets the size of the connection pool and the time limits of the storage
+type PoolConfig struct {
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+	ConnectTimeout  time.Duration
+	QueryTimeout    time.Duration // every storage call must complete within it
+}
+
+// Prepared statements names, the statements are prepared on every connection of the pool
 var (
-	saveUser    = "saveUser"
-	getUserData = "getUserData"
-	updateStat  = "updateStat"
-	getStat     = "getStat"
+	saveUser     = "saveUser"
+	getUserData  = "getUserData"
+	updateStat   = "updateStat"
+	getStat      = "getStat"
+	lockStats    = "lockStats"
+	saveMatch    = "saveMatch"
+	getMatches   = "getMatches"
+	countMatches = "countMatches"
+	getResults   = "getResults"
+	resetRatings = "resetRatings"
+
+	getLeaderboard   = "getLeaderboard"
+	countLeaderboard = "countLeaderboard"
+	getRank          = "getRank"
+
+	saveMatchEvent = "saveMatchEvent"
+	getMatch       = "getMatch"
+	getMatchEvents = "getMatchEvents"
 )
 
-func New(storagePath string) (*Storage, error) {
+// leaderboardOrder ranks the players, the login makes the order of equal players deterministic
+const leaderboardOrder = `rating DESC, wins DESC, losses ASC, user_login ASC`
+
+// statements are prepared on every new connection of the pool
+var statements = map[string]string{
+	saveUser: `
+		INSERT INTO users(login, password_hash) VALUES ($1, $2);
+	`,
+	getUserData: `
+		SELECT password_hash FROM users WHERE login = $1
+	`,
+	updateStat: `
+		INSERT INTO players_statistics(user_login, wins, losses, rating, rating_deviation, rating_volatility) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_login) DO UPDATE SET wins = $2, losses = $3, rating = $4, rating_deviation = $5, rating_volatility = $6;
+	`,
+	getStat: `
+		SELECT wins, losses, rating, rating_deviation, rating_volatility FROM players_statistics WHERE user_login = $1
+	`,
+	// the rows are locked in the order of the logins, so that two results of the same players cannot deadlock
+	lockStats: `
+		SELECT user_login, wins, losses, rating, rating_deviation, rating_volatility FROM players_statistics
+		WHERE user_login IN ($1, $2) ORDER BY user_login FOR UPDATE
+	`,
+	saveMatch: `
+		INSERT INTO matches(winner_login, loser_login, started_at, ended_at, shots, winner_rating_delta, loser_rating_delta, end_reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id;
+	`,
+	saveMatchEvent: `
+		INSERT INTO match_events(match_id, seq, kind, player, x, y, hit, destroy, fleet, reason, at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+	`,
+	getMatch: `
+		SELECT id, winner_login, loser_login, COALESCE(started_at, ended_at), ended_at, shots, winner_rating_delta, loser_rating_delta, end_reason
+		FROM matches WHERE id = $1
+	`,
+	getMatchEvents: `
+		SELECT seq, kind, player, x, y, hit, destroy, fleet, reason, at
+		FROM match_events WHERE match_id = $1 ORDER BY seq
+	`,
+	getMatches: `
+		SELECT id, winner_login, loser_login, COALESCE(started_at, ended_at), ended_at, shots, winner_rating_delta, loser_rating_delta, end_reason
+		FROM matches WHERE winner_login = $1 OR loser_login = $1
+		ORDER BY ended_at DESC, id DESC LIMIT $2 OFFSET $3
+	`,
+	countMatches: `
+		SELECT count(*) FROM matches WHERE winner_login = $1 OR loser_login = $1
+	`,
+	getResults: `
+		SELECT winner_login, loser_login FROM matches ORDER BY ended_at, id
+	`,
+	resetRatings: `
+		UPDATE players_statistics SET rating = $1, rating_deviation = $2, rating_volatility = $3;
+	`,
+	getLeaderboard: `
+		SELECT user_login, wins, losses, rating, rating_deviation, rating_volatility
+		FROM players_statistics WHERE wins + losses > 0
+		ORDER BY ` + leaderboardOrder + ` LIMIT $1 OFFSET $2
+	`,
+	countLeaderboard: `
+		SELECT count(*) FROM players_statistics WHERE wins + losses > 0
+	`,
+	getRank: `
+		SELECT rank FROM (
+			SELECT user_login, ROW_NUMBER() OVER (ORDER BY ` + leaderboardOrder + `) AS rank
+			FROM players_statistics WHERE wins + losses > 0
+		) ranked WHERE user_login = $1
+	`,
+}
+
+func New(storagePath string, pool PoolConfig) (*Storage, error) {
 	const op = "storage.postgres.New"
 
-	db, err := pgx.Connect(context.Background(), storagePath)
+	// The schema is brought up to date on start, a database migrated by a newer server is refused.
+	// It is done before the pool is opened, the statements can only be prepared on the latest schema.
+	migrator, err := NewMigrator(storagePath)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-
-	createTablesStmt := []string{
-		`CREATE TABLE IF NOT EXISTS users(
-            id SERIAL PRIMARY KEY,
-            login TEXT NOT NULL UNIQUE,
-            password_hash TEXT NOT NULL
-        );`,
-		`CREATE INDEX IF NOT EXISTS idx_users_login ON users(login);`,
-		`CREATE TABLE IF NOT EXISTS players_statistics(
-            id SERIAL PRIMARY KEY,
-            user_login TEXT REFERENCES users(login),
-            wins INTEGER NOT NULL DEFAULT 0,
-            losses INTEGER NOT NULL DEFAULT 0,
-            rating INTEGER NOT NULL DEFAULT 0
-        );`,
-		`CREATE INDEX IF NOT EXISTS idx_player_statistics_user_login ON players_statistics(user_login);`,
-		`DROP TRIGGER IF EXISTS create_player_statistics_trigger ON users;
-
-		CREATE OR REPLACE FUNCTION create_player_statistics() RETURNS TRIGGER AS $$
-		BEGIN
-		  INSERT INTO players_statistics(user_login, wins, losses, rating) 
-		  VALUES (NEW.login, 0, 0, 0);
-		  RETURN NEW;
-		END;
-		$$ LANGUAGE plpgsql;
-		
-		CREATE TRIGGER create_player_statistics_trigger
-		AFTER INSERT ON users
-		FOR EACH ROW
-		EXECUTE FUNCTION create_player_statistics();`,
-	}
-
-	// batch := pgx.Batch{}
-
-	// for i := range createTablesStmt {
-	// 	batch.Queue(createTablesStmt[i])
-	// }
-
-	// fmt.Println("Creating tables...")
-
-	// results := db.SendBatch(context.Background(), &batch)
-	// defer results.Close()
-
-	// for {
-	// 	_, err := results.Exec()
-	// 	if err == pgx.ErrNoRows {
-	// 		break
-	// 	}
-	// 	if err != nil {
-	// 		fmt.Printf("%s: %v\n", op, err)
-	// 		return nil, fmt.Errorf("%s: %w", op, err)
-	// 	}
-	// }
-	for i := range createTablesStmt {
-		_, err := db.Exec(context.Background(), createTablesStmt[i])
-		if err != nil {
-			fmt.Printf("Error executing statement %d: %v\n", i, err)
-			return nil, fmt.Errorf("%s: %w", op, err)
-		}
-	}
-
-	// Prepare statements for future use
-	_, err = db.Prepare(context.Background(), saveUser, `
-		INSERT INTO users(login, password_hash) VALUES ($1, $2);
-	`)
+	_, err = migrator.Up()
+	migrator.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = db.Prepare(context.Background(), getUserData, `
-		SELECT password_hash FROM users WHERE login = $1
-	`)
+	cfg, err := pgxpool.ParseConfig(storagePath)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	cfg.MaxConns = pool.MaxConns
+	cfg.MinConns = pool.MinConns
+	cfg.MaxConnLifetime = pool.MaxConnLifetime
+	cfg.MaxConnIdleTime = pool.MaxConnIdleTime
+	cfg.ConnConfig.ConnectTimeout = pool.ConnectTimeout
+	cfg.AfterConnect = prepare
+
+	ctx, cancel := context.WithTimeout(context.Background(), pool.ConnectTimeout)
+	defer cancel()
 
-	_, err = db.Prepare(context.Background(), updateStat, `
-		INSERT INTO players_statistics(user_login, wins, losses, rating) VALUES ($1, $2, $3, $4)
-		ON CONFLICT (user_login) DO UPDATE SET wins = $2, losses = $3, rating = $4;
-	`)
+	db, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-
-	_, err = db.Prepare(context.Background(), getStat, `
-		SELECT wins, losses, rating FROM players_statistics WHERE user_login = $1
-	`)
+	// the pool connects lazily, a bad address or password must stop the start
+	err = db.Ping(ctx)
 	if err != nil {
+		db.Close()
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &Storage{db: db}, nil
+	return &Storage{db: db, queryTimeout: pool.QueryTimeout}, nil
 }
 
-func (s *Storage) SaveUser(login string, passHash []byte) error {
-	const op = "storage.postgres.Login"
+// prepare prepares the statements on a new connection of the pool
+func prepare(ctx context.Context, conn *pgx.Conn) error {
+	for name, sql := range statements {
+		_, err := conn.Prepare(ctx, name, sql)
+		if err != nil {
+			return fmt.Errorf("prepare %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// withTimeout limits the storage call by the query timeout
+func (s *Storage) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, s.queryTimeout)
+}
+
+func (s *Storage) SaveUser(ctx context.Context, login string, passHash []byte) error {
+	const op = "storage.postgres.SaveUser"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
-	_, err := s.db.Exec(context.Background(), saveUser, login, passHash)
+	_, err := s.db.Exec(ctx, saveUser, login, passHash)
 	if err != nil {
 		// Check if user already exists
 		var pgErr *pgconn.PgError
@@ -143,11 +202,14 @@ func (s *Storage) SaveUser(login string, passHash []byte) error {
 	return nil
 }
 
-func (s *Storage) GetUserData(login string) ([]byte, error) {
+func (s *Storage) GetUserData(ctx context.Context, login string) ([]byte, error) {
 	const op = "storage.postgres.GetUserData"
 
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
 	var passHash []byte
-	err := s.db.QueryRow(context.Background(), getUserData, login).Scan(&passHash)
+	err := s.db.QueryRow(ctx, getUserData, login).Scan(&passHash)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, storage.ErrUserNotFound
@@ -158,10 +220,14 @@ func (s *Storage) GetUserData(login string) ([]byte, error) {
 	return passHash, nil
 }
 
-func (s *Storage) UpdateStat(userLogin string, stat game.Statistics) error {
+func (s *Storage) UpdateStat(ctx context.Context, userLogin string, stat game.Statistics) error {
 	const op = "storage.postgres.UpdateStat"
 
-	_, err := s.db.Exec(context.Background(), updateStat, userLogin, stat.Wins, stat.Losses, stat.Rating)
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.Exec(ctx, updateStat, userLogin, stat.Wins, stat.Losses,
+		stat.Rating.Value, stat.Rating.Deviation, stat.Rating.Volatility)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -169,11 +235,15 @@ func (s *Storage) UpdateStat(userLogin string, stat game.Statistics) error {
 	return nil
 }
 
-func (s *Storage) GetStat(login string) (game.Statistics, error) {
+func (s *Storage) GetStat(ctx context.Context, login string) (game.Statistics, error) {
 	const op = "storage.postgres.GetStat"
 
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
 	var stat game.Statistics
-	err := s.db.QueryRow(context.Background(), getStat, login).Scan(&stat.Wins, &stat.Losses, &stat.Rating)
+	err := s.db.QueryRow(ctx, getStat, login).Scan(&stat.Wins, &stat.Losses,
+		&stat.Rating.Value, &stat.Rating.Deviation, &stat.Rating.Volatility)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return game.Statistics{}, storage.ErrUserNotFound
@@ -184,6 +254,251 @@ func (s *Storage) GetStat(login string) (game.Statistics, error) {
 	return stat, nil
 }
 
-func (s *Storage) Close() error {
-	return s.db.Close(context.Background())
+// RecordResult locks the statistics of both players, lets apply update them and the match
+// and saves them with the match in one transaction
+func (s *Storage) RecordResult(ctx context.Context, match game.Match, apply game.ApplyResult) error {
+	const op = "storage.postgres.RecordResult"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, lockStats, match.Winner, match.Loser)
+		if err != nil {
+			return err
+		}
+		stats := make(map[string]game.Statistics, 2)
+		var (
+			login string
+			stat  game.Statistics
+		)
+		_, err = pgx.ForEachRow(rows, []any{&login, &stat.Wins, &stat.Losses,
+			&stat.Rating.Value, &stat.Rating.Deviation, &stat.Rating.Volatility}, func() error {
+			stats[login] = stat
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		winnerStat, ok1 := stats[match.Winner]
+		loserStat, ok2 := stats[match.Loser]
+		if !ok1 || !ok2 {
+			return storage.ErrUserNotFound
+		}
+
+		apply(&winnerStat, &loserStat, &match)
+
+		for _, u := range []struct {
+			login string
+			stat  game.Statistics
+		}{{match.Winner, winnerStat}, {match.Loser, loserStat}} {
+			_, err = tx.Exec(ctx, updateStat, u.login, u.stat.Wins, u.stat.Losses,
+				u.stat.Rating.Value, u.stat.Rating.Deviation, u.stat.Rating.Volatility)
+			if err != nil {
+				return err
+			}
+		}
+
+		return insertMatch(ctx, tx, match)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// insertMatch saves the match with its events within the transaction
+func insertMatch(ctx context.Context, tx pgx.Tx, match game.Match) error {
+	var id int64
+	err := tx.QueryRow(ctx, saveMatch, match.Winner, match.Loser, match.StartedAt, match.EndedAt,
+		match.Shots, match.WinnerRatingDelta, match.LoserRatingDelta, string(match.EndReason)).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	batch := &pgx.Batch{}
+	for _, e := range match.Events {
+		var fleet []byte
+		if e.Fleet != nil {
+			fleet, err = json.Marshal(e.Fleet)
+			if err != nil {
+				return err
+			}
+		}
+		batch.Queue(saveMatchEvent, id, e.Seq, string(e.Type), e.Player, e.X, e.Y, e.Hit, e.Destroy,
+			fleet, string(e.Reason), e.At)
+	}
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+func (s *Storage) GetReplay(ctx context.Context, matchID int64) (game.Match, error) {
+	const op = "storage.postgres.GetReplay"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var m game.Match
+	var reason string
+	err := s.db.QueryRow(ctx, getMatch, matchID).Scan(&m.ID, &m.Winner, &m.Loser, &m.StartedAt,
+		&m.EndedAt, &m.Shots, &m.WinnerRatingDelta, &m.LoserRatingDelta, &reason)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return game.Match{}, game.ErrMatchNotFound
+		}
+		return game.Match{}, fmt.Errorf("%s: %w", op, err)
+	}
+	m.EndReason = game.EndReason(reason)
+
+	rows, err := s.db.Query(ctx, getMatchEvents, matchID)
+	if err != nil {
+		return game.Match{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	m.Events, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (game.ReplayEvent, error) {
+		var e game.ReplayEvent
+		var kind, reason string
+		var fleet []byte
+		err := row.Scan(&e.Seq, &kind, &e.Player, &e.X, &e.Y, &e.Hit, &e.Destroy, &fleet, &reason, &e.At)
+		if err != nil {
+			return e, err
+		}
+		e.Type, e.Reason = game.ReplayEventType(kind), game.EndReason(reason)
+		if fleet != nil {
+			err = json.Unmarshal(fleet, &e.Fleet)
+		}
+		return e, err
+	})
+	if err != nil {
+		return game.Match{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return m, nil
+}
+
+func (s *Storage) GetMatches(ctx context.Context, login string, limit, offset int) ([]game.Match, int, error) {
+	const op = "storage.postgres.GetMatches"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var total int
+	err := s.db.QueryRow(ctx, countMatches, login).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.Query(ctx, getMatches, login, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	matches, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (game.Match, error) {
+		var m game.Match
+		var reason string
+		err := row.Scan(&m.ID, &m.Winner, &m.Loser, &m.StartedAt, &m.EndedAt, &m.Shots,
+			&m.WinnerRatingDelta, &m.LoserRatingDelta, &reason)
+		m.EndReason = game.EndReason(reason)
+		return m, err
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return matches, total, nil
+}
+
+func (s *Storage) GetResults(ctx context.Context) ([]game.Result, error) {
+	const op = "storage.postgres.GetResults"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, getResults)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (game.Result, error) {
+		var res game.Result
+		err := row.Scan(&res.Winner, &res.Loser)
+		return res, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
+
+func (s *Storage) ResetRatings(ctx context.Context, initial rating.Rating) error {
+	const op = "storage.postgres.ResetRatings"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.Exec(ctx, resetRatings, initial.Value, initial.Deviation, initial.Volatility)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetLeaderboard(ctx context.Context, limit, offset int) ([]game.LeaderboardEntry, int, error) {
+	const op = "storage.postgres.GetLeaderboard"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var total int
+	err := s.db.QueryRow(ctx, countLeaderboard).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.Query(ctx, getLeaderboard, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rank := offset
+	entries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (game.LeaderboardEntry, error) {
+		var e game.LeaderboardEntry
+		err := row.Scan(&e.Login, &e.Stat.Wins, &e.Stat.Losses,
+			&e.Stat.Rating.Value, &e.Stat.Rating.Deviation, &e.Stat.Rating.Volatility)
+		rank++
+		e.Rank = rank
+		return e, err
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, total, nil
+}
+
+func (s *Storage) GetRank(ctx context.Context, login string) (int, error) {
+	const op = "storage.postgres.GetRank"
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var rank int
+	err := s.db.QueryRow(ctx, getRank, login).Scan(&rank)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rank, nil
+}
+
+func (s *Storage) Close() {
+	s.db.Close()
 }
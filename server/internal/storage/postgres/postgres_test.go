@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"battle-ship_server/internal/storage/storagetest"
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestConformance runs against the database in TEST_POSTGRES_URL, all its data is deleted
+func TestConformance(t *testing.T) {
+	url := os.Getenv("TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("TEST_POSTGRES_URL is not set")
+	}
+
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		s, err := New(url, PoolConfig{
+			MaxConns:        10,
+			MaxConnLifetime: time.Hour,
+			MaxConnIdleTime: time.Minute,
+			ConnectTimeout:  5 * time.Second,
+			QueryTimeout:    5 * time.Second,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(s.Close)
+
+		_, err = s.db.Exec(context.Background(), `TRUNCATE users, players_statistics, matches, match_events RESTART IDENTITY CASCADE`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	})
+}
@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"battle-ship_server/internal/storage/migrate"
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"time"
+)
+
+// ErrSchemaTooNew is returned when the database file was written by a newer server than this one
+var ErrSchemaTooNew = migrate.ErrSchemaTooNew
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// Migrator applies and rolls back the migrations of the sqlite storage, the applied ones are kept in schema_migrations
+type Migrator struct {
+	*migrate.Migrator
+	db *sql.DB
+}
+
+// NewMigrator opens the database file to migrate its schema
+func NewMigrator(file string) (*Migrator, error) {
+	const op = "storage.sqlite.NewMigrator"
+
+	db, err := open(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	m, err := newMigrator(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Migrator{Migrator: m, db: db}, nil
+}
+
+func newMigrator(db *sql.DB) (*migrate.Migrator, error) {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations(
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at INTEGER NOT NULL
+		)`)
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.New(migrationDB{db: db}, migrationsFS)
+}
+
+func (m *Migrator) Close() {
+	m.db.Close()
+}
+
+// migrationDB runs the migrations on the database file, the times are unix nanoseconds
+type migrationDB struct {
+	db *sql.DB
+}
+
+func (m migrationDB) Applied(ctx context.Context) (map[int]time.Time, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at int64
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = time.Unix(0, at)
+	}
+	return applied, rows.Err()
+}
+
+func (m migrationDB) Apply(ctx context.Context, script string, version int, name string, up bool) error {
+	return inTx(ctx, m.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, script)
+		if err != nil {
+			return err
+		}
+		if up {
+			_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations(version, name, applied_at) VALUES (?, ?, ?)`,
+				version, name, time.Now().UnixNano())
+		} else {
+			_, err = tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, version)
+		}
+		return err
+	})
+}
+
+// Lock does nothing, the file is served by a single server
+func (m migrationDB) Lock(context.Context) (unlock func(), err error) {
+	return func() {}, nil
+}
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS create_player_statistics_trigger;
+DROP TABLE IF EXISTS match_events;
+DROP TABLE IF EXISTS matches;
+DROP TABLE IF EXISTS players_statistics;
+DROP TABLE IF EXISTS users;
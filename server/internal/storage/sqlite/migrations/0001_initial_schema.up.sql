@@ -0,0 +1,54 @@
+-- The schema of the postgres storage at its migration 0002, the times are unix nanoseconds.
+
+CREATE TABLE users(
+    id INTEGER PRIMARY KEY,
+    login TEXT NOT NULL UNIQUE,
+    password_hash BLOB NOT NULL
+);
+
+CREATE TABLE players_statistics(
+    id INTEGER PRIMARY KEY,
+    user_login TEXT UNIQUE REFERENCES users(login),
+    wins INTEGER NOT NULL DEFAULT 0,
+    losses INTEGER NOT NULL DEFAULT 0,
+    rating REAL NOT NULL DEFAULT 1500,
+    rating_deviation REAL NOT NULL DEFAULT 350,
+    rating_volatility REAL NOT NULL DEFAULT 0.06
+);
+CREATE INDEX idx_player_statistics_rating ON players_statistics(rating DESC, wins DESC, losses ASC, user_login ASC);
+
+CREATE TABLE matches(
+    id INTEGER PRIMARY KEY,
+    winner_login TEXT NOT NULL REFERENCES users(login),
+    loser_login TEXT NOT NULL REFERENCES users(login),
+    started_at INTEGER NOT NULL,
+    ended_at INTEGER NOT NULL,
+    shots INTEGER NOT NULL DEFAULT 0,
+    winner_rating_delta REAL NOT NULL DEFAULT 0,
+    loser_rating_delta REAL NOT NULL DEFAULT 0,
+    end_reason TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX idx_matches_winner_login ON matches(winner_login);
+CREATE INDEX idx_matches_loser_login ON matches(loser_login);
+
+CREATE TABLE match_events(
+    match_id INTEGER NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    seq INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    player TEXT NOT NULL,
+    x INTEGER NOT NULL DEFAULT 0,
+    y INTEGER NOT NULL DEFAULT 0,
+    hit INTEGER NOT NULL DEFAULT 0,
+    destroy INTEGER NOT NULL DEFAULT 0,
+    fleet TEXT, -- JSON
+    reason TEXT NOT NULL DEFAULT '',
+    at INTEGER NOT NULL,
+    PRIMARY KEY (match_id, seq)
+);
+
+CREATE TRIGGER create_player_statistics_trigger
+AFTER INSERT ON users
+FOR EACH ROW
+BEGIN
+  INSERT INTO players_statistics(user_login) VALUES (NEW.login);
+END;
@@ -0,0 +1,434 @@
+// Package sqlite keeps the users, the statistics and the matches in a single file,
+// for the deployments on one host that do not want to run postgres
+package sqlite
+
+import (
+	"battle-ship_server/internal/service/game"
+	"battle-ship_server/internal/service/game/rating"
+	"battle-ship_server/internal/storage"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	sqlite "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type Storage struct {
+	db *sql.DB
+}
+
+// leaderboardOrder ranks the players, the login makes the order of equal players deterministic
+const leaderboardOrder = `rating DESC, wins DESC, losses ASC, user_login ASC`
+
+const (
+	saveUser    = `INSERT INTO users(login, password_hash) VALUES (?, ?)`
+	getUserData = `SELECT password_hash FROM users WHERE login = ?`
+	updateStat  = `
+		INSERT INTO players_statistics(user_login, wins, losses, rating, rating_deviation, rating_volatility) VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+		ON CONFLICT (user_login) DO UPDATE SET wins = ?2, losses = ?3, rating = ?4, rating_deviation = ?5, rating_volatility = ?6`
+	getStat   = `SELECT wins, losses, rating, rating_deviation, rating_volatility FROM players_statistics WHERE user_login = ?`
+	saveMatch = `
+		INSERT INTO matches(winner_login, loser_login, started_at, ended_at, shots, winner_rating_delta, loser_rating_delta, end_reason)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	saveMatchEvent = `
+		INSERT INTO match_events(match_id, seq, kind, player, x, y, hit, destroy, fleet, reason, at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	getMatch = `
+		SELECT id, winner_login, loser_login, started_at, ended_at, shots, winner_rating_delta, loser_rating_delta, end_reason
+		FROM matches WHERE id = ?`
+	getMatchEvents = `
+		SELECT seq, kind, player, x, y, hit, destroy, fleet, reason, at
+		FROM match_events WHERE match_id = ? ORDER BY seq`
+	getMatches = `
+		SELECT id, winner_login, loser_login, started_at, ended_at, shots, winner_rating_delta, loser_rating_delta, end_reason
+		FROM matches WHERE winner_login = ?1 OR loser_login = ?1
+		ORDER BY ended_at DESC, id DESC LIMIT ?2 OFFSET ?3`
+	countMatches   = `SELECT count(*) FROM matches WHERE winner_login = ?1 OR loser_login = ?1`
+	getResults     = `SELECT winner_login, loser_login FROM matches ORDER BY ended_at, id`
+	resetRatings   = `UPDATE players_statistics SET rating = ?, rating_deviation = ?, rating_volatility = ?`
+	getLeaderboard = `
+		SELECT user_login, wins, losses, rating, rating_deviation, rating_volatility
+		FROM players_statistics WHERE wins + losses > 0
+		ORDER BY ` + leaderboardOrder + ` LIMIT ? OFFSET ?`
+	countLeaderboard = `SELECT count(*) FROM players_statistics WHERE wins + losses > 0`
+	getRank          = `
+		SELECT rank FROM (
+			SELECT user_login, ROW_NUMBER() OVER (ORDER BY ` + leaderboardOrder + `) AS rank
+			FROM players_statistics WHERE wins + losses > 0
+		) ranked WHERE user_login = ?`
+)
+
+// New opens the database file, creating it if needed, and brings its schema up to date.
+// A file migrated by a newer server is refused.
+func New(file string) (*Storage, error) {
+	const op = "storage.sqlite.New"
+
+	db, err := open(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	m, err := newMigrator(db)
+	if err == nil {
+		_, err = m.Up()
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Storage{db: db}, nil
+}
+
+// open opens the database file, creating it if needed
+func open(file string) (*sql.DB, error) {
+	dsn := "file:" + file + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite has a single writer, the transactions wait for each other on the connection
+	// instead of failing with SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// inTx runs f in a transaction, committed if f succeeds and rolled back otherwise
+func inTx(ctx context.Context, db *sql.DB, f func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = f(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Storage) SaveUser(ctx context.Context, login string, passHash []byte) error {
+	const op = "storage.sqlite.SaveUser"
+
+	_, err := s.db.ExecContext(ctx, saveUser, login, passHash)
+	if err != nil {
+		// Check if user already exists
+		var sqliteErr *sqlite.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE {
+			return storage.ErrUserExists
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetUserData(ctx context.Context, login string) ([]byte, error) {
+	const op = "storage.sqlite.GetUserData"
+
+	var passHash []byte
+	err := s.db.QueryRowContext(ctx, getUserData, login).Scan(&passHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return passHash, nil
+}
+
+func (s *Storage) UpdateStat(ctx context.Context, userLogin string, stat game.Statistics) error {
+	const op = "storage.sqlite.UpdateStat"
+
+	_, err := s.db.ExecContext(ctx, updateStat, userLogin, stat.Wins, stat.Losses,
+		stat.Rating.Value, stat.Rating.Deviation, stat.Rating.Volatility)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetStat(ctx context.Context, login string) (game.Statistics, error) {
+	const op = "storage.sqlite.GetStat"
+
+	stat, err := scanStat(s.db.QueryRowContext(ctx, getStat, login))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return game.Statistics{}, storage.ErrUserNotFound
+		}
+		return game.Statistics{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stat, nil
+}
+
+func scanStat(row *sql.Row) (game.Statistics, error) {
+	var stat game.Statistics
+	err := row.Scan(&stat.Wins, &stat.Losses, &stat.Rating.Value, &stat.Rating.Deviation, &stat.Rating.Volatility)
+	return stat, err
+}
+
+// RecordResult updates the statistics of both players and saves the match in one transaction.
+// The connection is not shared, so nobody else reads the statistics until the transaction ends.
+func (s *Storage) RecordResult(ctx context.Context, match game.Match, apply game.ApplyResult) error {
+	const op = "storage.sqlite.RecordResult"
+
+	err := inTx(ctx, s.db, func(tx *sql.Tx) error {
+		winnerStat, err := scanStat(tx.QueryRowContext(ctx, getStat, match.Winner))
+		if err != nil {
+			return err
+		}
+		loserStat, err := scanStat(tx.QueryRowContext(ctx, getStat, match.Loser))
+		if err != nil {
+			return err
+		}
+
+		apply(&winnerStat, &loserStat, &match)
+
+		for _, u := range []struct {
+			login string
+			stat  game.Statistics
+		}{{match.Winner, winnerStat}, {match.Loser, loserStat}} {
+			_, err = tx.ExecContext(ctx, updateStat, u.login, u.stat.Wins, u.stat.Losses,
+				u.stat.Rating.Value, u.stat.Rating.Deviation, u.stat.Rating.Volatility)
+			if err != nil {
+				return err
+			}
+		}
+
+		return insertMatch(ctx, tx, match)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrUserNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// insertMatch saves the match with its events within the transaction
+func insertMatch(ctx context.Context, tx *sql.Tx, match game.Match) error {
+	res, err := tx.ExecContext(ctx, saveMatch, match.Winner, match.Loser, match.StartedAt.UnixNano(),
+		match.EndedAt.UnixNano(), match.Shots, match.WinnerRatingDelta, match.LoserRatingDelta, string(match.EndReason))
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range match.Events {
+		var fleet []byte
+		if e.Fleet != nil {
+			fleet, err = json.Marshal(e.Fleet)
+			if err != nil {
+				return err
+			}
+		}
+		_, err = tx.ExecContext(ctx, saveMatchEvent, id, e.Seq, string(e.Type), e.Player, e.X, e.Y, e.Hit, e.Destroy,
+			nullString(fleet), string(e.Reason), e.At.UnixNano())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func nullString(b []byte) sql.NullString {
+	return sql.NullString{String: string(b), Valid: b != nil}
+}
+
+func (s *Storage) GetReplay(ctx context.Context, matchID int64) (game.Match, error) {
+	const op = "storage.sqlite.GetReplay"
+
+	m, err := scanMatch(s.db.QueryRowContext(ctx, getMatch, matchID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return game.Match{}, game.ErrMatchNotFound
+		}
+		return game.Match{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, getMatchEvents, matchID)
+	if err != nil {
+		return game.Match{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			e            game.ReplayEvent
+			kind, reason string
+			fleet        sql.NullString
+			at           int64
+		)
+		err = rows.Scan(&e.Seq, &kind, &e.Player, &e.X, &e.Y, &e.Hit, &e.Destroy, &fleet, &reason, &at)
+		if err != nil {
+			return game.Match{}, fmt.Errorf("%s: %w", op, err)
+		}
+		e.Type, e.Reason, e.At = game.ReplayEventType(kind), game.EndReason(reason), time.Unix(0, at)
+		if fleet.Valid {
+			err = json.Unmarshal([]byte(fleet.String), &e.Fleet)
+			if err != nil {
+				return game.Match{}, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+		m.Events = append(m.Events, e)
+	}
+	err = rows.Err()
+	if err != nil {
+		return game.Match{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return m, nil
+}
+
+// scanner is a *sql.Row or *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanMatch(row scanner) (game.Match, error) {
+	var (
+		m                  game.Match
+		startedAt, endedAt int64
+		reason             string
+	)
+	err := row.Scan(&m.ID, &m.Winner, &m.Loser, &startedAt, &endedAt, &m.Shots,
+		&m.WinnerRatingDelta, &m.LoserRatingDelta, &reason)
+	m.StartedAt, m.EndedAt, m.EndReason = time.Unix(0, startedAt), time.Unix(0, endedAt), game.EndReason(reason)
+	return m, err
+}
+
+func (s *Storage) GetMatches(ctx context.Context, login string, limit, offset int) ([]game.Match, int, error) {
+	const op = "storage.sqlite.GetMatches"
+
+	var total int
+	err := s.db.QueryRowContext(ctx, countMatches, login).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, getMatches, login, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	matches := make([]game.Match, 0)
+	for rows.Next() {
+		m, err := scanMatch(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+		matches = append(matches, m)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return matches, total, nil
+}
+
+func (s *Storage) GetResults(ctx context.Context) ([]game.Result, error) {
+	const op = "storage.sqlite.GetResults"
+
+	rows, err := s.db.QueryContext(ctx, getResults)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	results := make([]game.Result, 0)
+	for rows.Next() {
+		var res game.Result
+		err = rows.Scan(&res.Winner, &res.Loser)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		results = append(results, res)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
+
+func (s *Storage) ResetRatings(ctx context.Context, initial rating.Rating) error {
+	const op = "storage.sqlite.ResetRatings"
+
+	_, err := s.db.ExecContext(ctx, resetRatings, initial.Value, initial.Deviation, initial.Volatility)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetLeaderboard(ctx context.Context, limit, offset int) ([]game.LeaderboardEntry, int, error) {
+	const op = "storage.sqlite.GetLeaderboard"
+
+	var total int
+	err := s.db.QueryRowContext(ctx, countLeaderboard).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, getLeaderboard, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	entries := make([]game.LeaderboardEntry, 0)
+	rank := offset
+	for rows.Next() {
+		var e game.LeaderboardEntry
+		err = rows.Scan(&e.Login, &e.Stat.Wins, &e.Stat.Losses,
+			&e.Stat.Rating.Value, &e.Stat.Rating.Deviation, &e.Stat.Rating.Volatility)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+		rank++
+		e.Rank = rank
+		entries = append(entries, e)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, total, nil
+}
+
+func (s *Storage) GetRank(ctx context.Context, login string) (int, error) {
+	const op = "storage.sqlite.GetRank"
+
+	var rank int
+	err := s.db.QueryRowContext(ctx, getRank, login).Scan(&rank)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rank, nil
+}
+
+func (s *Storage) Close() {
+	s.db.Close()
+}
@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"battle-ship_server/internal/storage/storagetest"
+	"path/filepath"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		s, err := New(filepath.Join(t.TempDir(), "battleship.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(s.Close)
+		return s
+	})
+}
+
+func TestMigrations(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "battleship.db")
+	s, err := New(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	m, err := NewMigrator(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	if v, err := m.Version(); err != nil || v != m.Latest() {
+		t.Fatalf("Version after New: %d, %v, want %d", v, err, m.Latest())
+	}
+	if n, err := m.Down(m.Latest()); err != nil || n != m.Latest() {
+		t.Fatalf("Down: %d, %v, want %d", n, err, m.Latest())
+	}
+	if v, err := m.Version(); err != nil || v != 0 {
+		t.Fatalf("Version after Down: %d, %v, want 0", v, err)
+	}
+	if n, err := m.Up(); err != nil || n != m.Latest() {
+		t.Fatalf("Up: %d, %v, want %d", n, err, m.Latest())
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mg := range status {
+		if mg.AppliedAt.IsZero() {
+			t.Errorf("migration %d_%s is not applied", mg.Version, mg.Name)
+		}
+	}
+}
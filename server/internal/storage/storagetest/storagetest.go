@@ -0,0 +1,382 @@
+// Package storagetest is the conformance suite every storage driver must pass
+package storagetest
+
+import (
+	"battle-ship_server/internal/service/auth"
+	"battle-ship_server/internal/service/game"
+	"battle-ship_server/internal/service/game/rating"
+	"battle-ship_server/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Storage is implemented by every storage driver
+type Storage interface {
+	auth.UserStorage
+	game.StatStorage
+}
+
+// NewStorage returns an empty storage, closed by the test cleanup
+type NewStorage func(t *testing.T) Storage
+
+// Run runs the suite, every test gets an empty storage
+func Run(t *testing.T, newStorage NewStorage) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, s Storage)
+	}{
+		{"Users", testUsers},
+		{"NewUserStat", testNewUserStat},
+		{"UpdateStat", testUpdateStat},
+		{"RecordResult", testRecordResult},
+		{"RecordResultUnknownUser", testRecordResultUnknownUser},
+		{"ConcurrentResults", testConcurrentResults},
+		{"Replay", testReplay},
+		{"Matches", testMatches},
+		{"Results", testResults},
+		{"ResetRatings", testResetRatings},
+		{"Leaderboard", testLeaderboard},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStorage(t))
+		})
+	}
+}
+
+var (
+	ctx      = context.Background()
+	initial  = rating.Rating{Value: 1500, Deviation: 350, Volatility: 0.06}
+	baseTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func saveUsers(t *testing.T, s Storage, logins ...string) {
+	t.Helper()
+	for _, login := range logins {
+		err := s.SaveUser(ctx, login, []byte("hash of "+login))
+		if err != nil {
+			t.Fatalf("SaveUser(%q): %v", login, err)
+		}
+	}
+}
+
+func getStat(t *testing.T, s Storage, login string) game.Statistics {
+	t.Helper()
+	stat, err := s.GetStat(ctx, login)
+	if err != nil {
+		t.Fatalf("GetStat(%q): %v", login, err)
+	}
+	return stat
+}
+
+// win records a win of the winner, the rating moves by 10 points
+func win(t *testing.T, s Storage, winner, loser string, endedAt time.Time) {
+	t.Helper()
+	match := game.Match{Winner: winner, Loser: loser, StartedAt: endedAt.Add(-time.Minute), EndedAt: endedAt}
+	err := s.RecordResult(ctx, match, func(w, l *game.Statistics, m *game.Match) {
+		w.Wins++
+		l.Losses++
+		w.Rating.Value += 10
+		l.Rating.Value -= 10
+		m.WinnerRatingDelta, m.LoserRatingDelta = 10, -10
+	})
+	if err != nil {
+		t.Fatalf("RecordResult(%s beats %s): %v", winner, loser, err)
+	}
+}
+
+func testUsers(t *testing.T, s Storage) {
+	saveUsers(t, s, "alice")
+
+	hash, err := s.GetUserData(ctx, "alice")
+	if err != nil || string(hash) != "hash of alice" {
+		t.Fatalf("GetUserData = %q, %v", hash, err)
+	}
+
+	err = s.SaveUser(ctx, "alice", []byte("other"))
+	if !errors.Is(err, storage.ErrUserExists) {
+		t.Fatalf("SaveUser of an existing user: %v, want ErrUserExists", err)
+	}
+
+	_, err = s.GetUserData(ctx, "bob")
+	if !errors.Is(err, storage.ErrUserNotFound) {
+		t.Fatalf("GetUserData of an unknown user: %v, want ErrUserNotFound", err)
+	}
+}
+
+func testNewUserStat(t *testing.T, s Storage) {
+	saveUsers(t, s, "alice")
+
+	stat := getStat(t, s, "alice")
+	want := game.Statistics{Rating: initial}
+	if stat != want {
+		t.Fatalf("statistics of a new user = %+v, want %+v", stat, want)
+	}
+
+	_, err := s.GetStat(ctx, "bob")
+	if !errors.Is(err, storage.ErrUserNotFound) {
+		t.Fatalf("GetStat of an unknown user: %v, want ErrUserNotFound", err)
+	}
+}
+
+func testUpdateStat(t *testing.T, s Storage) {
+	saveUsers(t, s, "alice")
+
+	want := game.Statistics{Wins: 3, Losses: 2, Rating: rating.Rating{Value: 1620.5, Deviation: 120, Volatility: 0.059}}
+	err := s.UpdateStat(ctx, "alice", want)
+	if err != nil {
+		t.Fatalf("UpdateStat: %v", err)
+	}
+	if stat := getStat(t, s, "alice"); stat != want {
+		t.Fatalf("statistics after the update = %+v, want %+v", stat, want)
+	}
+}
+
+func testRecordResult(t *testing.T, s Storage) {
+	saveUsers(t, s, "alice", "bob")
+
+	var gotWinner, gotLoser game.Statistics
+	match := game.Match{Winner: "alice", Loser: "bob", StartedAt: baseTime, EndedAt: baseTime.Add(time.Minute), Shots: 40,
+		EndReason: game.EndAllSunk}
+	err := s.RecordResult(ctx, match, func(w, l *game.Statistics, m *game.Match) {
+		gotWinner, gotLoser = *w, *l
+		w.Wins++
+		l.Losses++
+		w.Rating.Value, l.Rating.Value = 1516, 1484
+		m.WinnerRatingDelta, m.LoserRatingDelta = 16, -16
+	})
+	if err != nil {
+		t.Fatalf("RecordResult: %v", err)
+	}
+
+	if want := (game.Statistics{Rating: initial}); gotWinner != want || gotLoser != want {
+		t.Fatalf("apply got %+v and %+v, want the statistics of new users", gotWinner, gotLoser)
+	}
+	if stat := getStat(t, s, "alice"); stat.Wins != 1 || stat.Rating.Value != 1516 {
+		t.Fatalf("winner statistics = %+v", stat)
+	}
+	if stat := getStat(t, s, "bob"); stat.Losses != 1 || stat.Rating.Value != 1484 {
+		t.Fatalf("loser statistics = %+v", stat)
+	}
+
+	matches, total, err := s.GetMatches(ctx, "bob", 10, 0)
+	if err != nil || total != 1 || len(matches) != 1 {
+		t.Fatalf("GetMatches = %d of %d, %v", len(matches), total, err)
+	}
+	m := matches[0]
+	if m.ID == 0 || m.Winner != "alice" || m.Loser != "bob" || m.Shots != 40 || m.EndReason != game.EndAllSunk ||
+		m.WinnerRatingDelta != 16 || m.LoserRatingDelta != -16 ||
+		!m.StartedAt.Equal(match.StartedAt) || !m.EndedAt.Equal(match.EndedAt) {
+		t.Fatalf("saved match = %+v", m)
+	}
+}
+
+func testRecordResultUnknownUser(t *testing.T, s Storage) {
+	saveUsers(t, s, "alice")
+
+	err := s.RecordResult(ctx, game.Match{Winner: "alice", Loser: "ghost", StartedAt: baseTime, EndedAt: baseTime},
+		func(w, l *game.Statistics, m *game.Match) { w.Wins++ })
+	if !errors.Is(err, storage.ErrUserNotFound) {
+		t.Fatalf("RecordResult with an unknown player: %v, want ErrUserNotFound", err)
+	}
+	if stat := getStat(t, s, "alice"); stat.Wins != 0 {
+		t.Fatalf("statistics changed by a failed result: %+v", stat)
+	}
+}
+
+// testConcurrentResults checks that no update is lost when the results of the same players are recorded at once
+func testConcurrentResults(t *testing.T, s Storage) {
+	saveUsers(t, s, "alice", "bob", "carol")
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*n)
+	for i := 0; i < n; i++ {
+		for _, loser := range []string{"bob", "carol"} {
+			wg.Add(1)
+			go func(loser string) {
+				defer wg.Done()
+				match := game.Match{Winner: "alice", Loser: loser, StartedAt: baseTime, EndedAt: baseTime}
+				errs <- s.RecordResult(ctx, match, func(w, l *game.Statistics, m *game.Match) {
+					w.Wins++
+					l.Losses++
+				})
+			}(loser)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("RecordResult: %v", err)
+		}
+	}
+
+	if stat := getStat(t, s, "alice"); stat.Wins != 2*n {
+		t.Fatalf("wins = %d, want %d", stat.Wins, 2*n)
+	}
+	for _, loser := range []string{"bob", "carol"} {
+		if stat := getStat(t, s, loser); stat.Losses != n {
+			t.Fatalf("losses of %s = %d, want %d", loser, stat.Losses, n)
+		}
+	}
+}
+
+func testReplay(t *testing.T, s Storage) {
+	saveUsers(t, s, "alice", "bob")
+
+	fleet := [][]game.Point{{{X: 0, Y: 0}, {X: 0, Y: 1}}, {{X: 5, Y: 5}}}
+	events := []game.ReplayEvent{
+		{Seq: 0, Type: game.ReplayFleet, Player: "alice", Fleet: fleet, At: baseTime},
+		{Seq: 1, Type: game.ReplayFleet, Player: "bob", Fleet: fleet, At: baseTime.Add(time.Second)},
+		{Seq: 2, Type: game.ReplayShot, Player: "alice", X: 5, Y: 5, Hit: true, Destroy: true, At: baseTime.Add(2 * time.Second)},
+		{Seq: 3, Type: game.ReplayEnd, Player: "alice", Reason: game.EndForfeit, At: baseTime.Add(3 * time.Second)},
+	}
+	match := game.Match{Winner: "alice", Loser: "bob", StartedAt: baseTime, EndedAt: baseTime.Add(3 * time.Second),
+		Shots: 1, EndReason: game.EndForfeit, Events: events}
+	err := s.RecordResult(ctx, match, func(w, l *game.Statistics, m *game.Match) {})
+	if err != nil {
+		t.Fatalf("RecordResult: %v", err)
+	}
+
+	matches, _, err := s.GetMatches(ctx, "alice", 1, 0)
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("GetMatches = %v, %v", matches, err)
+	}
+	replay, err := s.GetReplay(ctx, matches[0].ID)
+	if err != nil {
+		t.Fatalf("GetReplay: %v", err)
+	}
+	if replay.Winner != "alice" || replay.EndReason != game.EndForfeit || len(replay.Events) != len(events) {
+		t.Fatalf("replay = %+v", replay)
+	}
+	for i, e := range replay.Events {
+		want := events[i]
+		if !e.At.Equal(want.At) {
+			t.Fatalf("event %d at %v, want %v", i, e.At, want.At)
+		}
+		e.At = want.At
+		if !reflect.DeepEqual(e, want) {
+			t.Fatalf("event %d = %+v, want %+v", i, e, want)
+		}
+	}
+
+	_, err = s.GetReplay(ctx, matches[0].ID+1000)
+	if !errors.Is(err, game.ErrMatchNotFound) {
+		t.Fatalf("GetReplay of an unknown match: %v, want ErrMatchNotFound", err)
+	}
+}
+
+func testMatches(t *testing.T, s Storage) {
+	saveUsers(t, s, "alice", "bob", "carol")
+
+	for i := 0; i < 5; i++ {
+		win(t, s, "alice", "bob", baseTime.Add(time.Duration(i)*time.Hour))
+	}
+	win(t, s, "carol", "bob", baseTime.Add(10*time.Hour))
+
+	matches, total, err := s.GetMatches(ctx, "bob", 4, 0)
+	if err != nil || total != 6 || len(matches) != 4 {
+		t.Fatalf("first page = %d of %d, %v", len(matches), total, err)
+	}
+	if matches[0].Winner != "carol" {
+		t.Fatalf("the latest match first, got %+v", matches[0])
+	}
+	for i := 1; i < len(matches); i++ {
+		if matches[i].EndedAt.After(matches[i-1].EndedAt) {
+			t.Fatalf("matches not ordered by the end: %v after %v", matches[i].EndedAt, matches[i-1].EndedAt)
+		}
+	}
+
+	matches, total, err = s.GetMatches(ctx, "bob", 4, 4)
+	if err != nil || total != 6 || len(matches) != 2 {
+		t.Fatalf("second page = %d of %d, %v", len(matches), total, err)
+	}
+
+	matches, total, err = s.GetMatches(ctx, "carol", 10, 0)
+	if err != nil || total != 1 || len(matches) != 1 {
+		t.Fatalf("matches of carol = %d of %d, %v", len(matches), total, err)
+	}
+}
+
+func testResults(t *testing.T, s Storage) {
+	saveUsers(t, s, "alice", "bob")
+
+	// recorded out of order, listed by the end
+	win(t, s, "bob", "alice", baseTime.Add(time.Hour))
+	win(t, s, "alice", "bob", baseTime)
+
+	results, err := s.GetResults(ctx)
+	if err != nil {
+		t.Fatalf("GetResults: %v", err)
+	}
+	want := []game.Result{{Winner: "alice", Loser: "bob"}, {Winner: "bob", Loser: "alice"}}
+	if !reflect.DeepEqual(results, want) {
+		t.Fatalf("GetResults = %v, want %v", results, want)
+	}
+}
+
+func testResetRatings(t *testing.T, s Storage) {
+	saveUsers(t, s, "alice", "bob")
+	win(t, s, "alice", "bob", baseTime)
+
+	reset := rating.Rating{Value: 1000, Deviation: 200, Volatility: 0.05}
+	err := s.ResetRatings(ctx, reset)
+	if err != nil {
+		t.Fatalf("ResetRatings: %v", err)
+	}
+
+	for _, login := range []string{"alice", "bob"} {
+		stat := getStat(t, s, login)
+		if stat.Rating != reset || stat.Wins+stat.Losses != 1 {
+			t.Fatalf("statistics of %s after the reset = %+v", login, stat)
+		}
+	}
+}
+
+func testLeaderboard(t *testing.T, s Storage) {
+	saveUsers(t, s, "alice", "bob", "carol", "dave", "erin")
+
+	// the ratings of bob and carol are equal, bob has fewer losses
+	stats := map[string]game.Statistics{
+		"alice": {Wins: 5, Losses: 1, Rating: rating.Rating{Value: 1600}},
+		"bob":   {Wins: 2, Losses: 2, Rating: rating.Rating{Value: 1500}},
+		"carol": {Wins: 2, Losses: 3, Rating: rating.Rating{Value: 1500}},
+		"dave":  {Wins: 0, Losses: 4, Rating: rating.Rating{Value: 1400}},
+	}
+	for login, stat := range stats {
+		err := s.UpdateStat(ctx, login, stat)
+		if err != nil {
+			t.Fatalf("UpdateStat(%q): %v", login, err)
+		}
+	}
+
+	entries, total, err := s.GetLeaderboard(ctx, 3, 0)
+	if err != nil || total != 4 {
+		t.Fatalf("GetLeaderboard total = %d, %v", total, err)
+	}
+	got := make([]string, 0, len(entries))
+	for _, e := range entries {
+		got = append(got, fmt.Sprintf("%d %s", e.Rank, e.Login))
+	}
+	if want := []string{"1 alice", "2 bob", "3 carol"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("first page = %v, want %v", got, want)
+	}
+
+	entries, _, err = s.GetLeaderboard(ctx, 3, 3)
+	if err != nil || len(entries) != 1 || entries[0].Rank != 4 || entries[0].Login != "dave" || entries[0].Stat != stats["dave"] {
+		t.Fatalf("second page = %+v, %v", entries, err)
+	}
+
+	for login, want := range map[string]int{"alice": 1, "carol": 3, "erin": 0, "ghost": 0} {
+		rank, err := s.GetRank(ctx, login)
+		if err != nil || rank != want {
+			t.Fatalf("GetRank(%q) = %d, %v, want %d", login, rank, err, want)
+		}
+	}
+}